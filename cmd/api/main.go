@@ -1,9 +1,12 @@
 package main
 
 import (
-	"cometScraper/tools/scraper/pkg/applicant"
 	"cometScraper/tools/scraper/pkg/crawler"
+	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	_ "cometScraper/docs"
@@ -13,15 +16,32 @@ import (
 	httpDelivery "cometScraper/delivery/http"
 	appMiddleware "cometScraper/delivery/middleware"
 	"cometScraper/infrastructure/datastore"
+	"cometScraper/infrastructure/health"
+	"cometScraper/infrastructure/tracing"
+	blobRepository "cometScraper/repository/blob"
 	pgsqlRepository "cometScraper/repository/pgsql"
 	redisRepository "cometScraper/repository/redis"
+	"cometScraper/transport/export"
 	"cometScraper/usecase"
 	"cometScraper/utils/logger"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
+const (
+	// healthCheckTimeout bounds each dependency check of the probes
+	healthCheckTimeout = 2 * time.Second
+	// shutdownGracePeriod is how long the running crawls are given to finish on shutdown,
+	// it should stay under the pod's terminationGracePeriodSeconds
+	shutdownGracePeriod = 25 * time.Second
+	// serverShutdownTimeout is how long the requests in flight are given once the crawls are drained
+	serverShutdownTimeout = 5 * time.Second
+	// schedulePollInterval is how often the due schedules are looked for
+	schedulePollInterval = time.Minute
+)
+
 func main() {
 	// Load config
 	configApp := config.LoadConfig()
@@ -29,24 +49,68 @@ func main() {
 	// Setup logger
 	appLogger := logger.NewApiLogger(configApp)
 	appLogger.InitLogger()
+	utils.SetSensitiveFields(configApp.SensitiveFields...)
+
+	// Setup tracing
+	shutdownTracing, err := tracing.Init(context.Background(), configApp.TraceExporter)
+	utils.PanicIfNeeded(err)
 
 	// Setup infra
 	dbInstance, err := datastore.NewDatabase(configApp.DatabaseURL)
 	utils.PanicIfNeeded(err)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(dbInstance, os.Args[2:])
+		_ = dbInstance.Close()
+		os.Exit(code)
+	}
+
+	if configApp.AutoMigrate {
+		utils.PanicIfNeeded(autoMigrate(dbInstance))
+	}
+
 	cacheInstance, err := datastore.NewCache(configApp.CacheURL)
 	utils.PanicIfNeeded(err)
 
 	// Setup repository
 	redisRepo := redisRepository.NewRedisRepository(cacheInstance)
 	cometScraperRepo := pgsqlRepository.NewPgsqlCometScraperRepository(dbInstance)
+	artifactRepo := pgsqlRepository.NewPgsqlArtifactRepository(dbInstance)
+	batchRepo := pgsqlRepository.NewPgsqlBatchRepository(dbInstance)
+	versionRepo := pgsqlRepository.NewPgsqlVersionRepository(dbInstance)
+	scheduleRepo := pgsqlRepository.NewPgsqlScheduleRepository(dbInstance)
+	blobRepo := blobRepository.NewLocalBlobRepository(configApp.BlobLocalDir)
+	if configApp.BlobStore == "s3" {
+		blobRepo = blobRepository.NewS3BlobRepository(blobRepository.S3Config{
+			Endpoint:  configApp.S3Endpoint,
+			Bucket:    configApp.S3Bucket,
+			Region:    configApp.S3Region,
+			AccessKey: configApp.S3AccessKey,
+			SecretKey: configApp.S3SecretKey,
+			Timeout:   time.Duration(configApp.S3Timeout) * time.Second,
+		}, nil)
+	}
 
 	//Setup Scraper
-	applicantInstance := applicant.NewApplicant()
-	cometCrawler := crawler.NewCometCrawler(configApp.Elements, applicantInstance)
+	stepPolicy, jobPolicy := crawler.DefaultStepPolicy, crawler.DefaultJobPolicy
+	if configApp.CrawlStepMaxAttempts > 0 {
+		stepPolicy.MaxAttempts = configApp.CrawlStepMaxAttempts
+	}
+	if configApp.CrawlMaxAttempts > 0 {
+		jobPolicy.MaxAttempts = configApp.CrawlMaxAttempts
+	}
+	if configApp.CrawlRetryBackoff > 0 {
+		stepPolicy.Backoff = time.Duration(configApp.CrawlRetryBackoff) * time.Second
+	}
+	cometCrawler := crawler.NewCometCrawler(configApp.Elements, redisRepo, appLogger, stepPolicy, jobPolicy)
+
+	// Setup export
+	exportRenderer, err := export.NewRenderer(configApp.MarkdownTemplatePath, configApp.HTMLTemplatePath)
+	utils.PanicIfNeeded(err)
 
 	// Setup usecase
-	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, cometCrawler)
+	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, cometCrawler, exportRenderer, blobRepo, artifactRepo, batchRepo, versionRepo, appLogger, configApp.CrawlBatchWorkers)
+	scheduleUC := usecase.NewScheduleUsecase(scheduleRepo, cometScraperUC, appLogger, configApp.ScheduleSecret)
 
 	// Setup app middleware
 	appMiddleware := appMiddleware.NewMiddleware(appLogger)
@@ -54,20 +118,86 @@ func main() {
 	// Setup route engine & middleware
 	e := echo.New()
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		Skipper: func(c echo.Context) bool {
+			return httpDelivery.StreamingPaths[c.Path()]
+		},
 		Timeout: time.Duration(configApp.ContextTimeout) * time.Second,
 	}))
 	e.Use(middleware.CORS())
+	e.Use(appMiddleware.Metrics())
 	e.Use(appMiddleware.RequestID())
+	e.Use(appMiddleware.Tracing())
 	e.Use(appMiddleware.Logger())
-	e.Use(middleware.Recover())
+	e.Use(appMiddleware.Recover())
 
 	// Setup handler
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 	e.GET("/", func(c echo.Context) error {
 		return c.String(http.StatusOK, "i am alive")
 	})
 
-	httpDelivery.NewCometScraperHandler(e, cometScraperUC)
+	httpDelivery.NewHealthHandler(e,
+		health.NewChecker(healthCheckTimeout).
+			Add("elements", health.Elements(configApp.Elements)),
+		health.NewChecker(healthCheckTimeout).
+			Add("postgres", health.Postgres(dbInstance)).
+			Add("redis", health.Redis(cacheInstance)).
+			Add("browser", crawler.CheckBrowser).
+			Add("elements", health.Elements(configApp.Elements)).
+			Add("queue", health.Queue(cometScraperUC.Running, configApp.CrawlMaxRunning)).
+			Add("shutdown", health.Draining(cometScraperUC.Draining)),
+	)
+	httpDelivery.NewCometScraperHandler(e, cometScraperUC, appMiddleware.RequireAdmin(), appMiddleware.TenantID())
+	httpDelivery.NewScheduleHandler(e, scheduleUC, appMiddleware.TenantID())
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if configApp.ScheduleSecret != "" {
+		pollInterval := schedulePollInterval
+		if configApp.SchedulePollInterval > 0 {
+			pollInterval = time.Duration(configApp.SchedulePollInterval) * time.Second
+		}
+		go scheduleUC.Run(schedulerCtx, pollInterval)
+	}
+
+	go func() {
+		if err := e.Start(":" + configApp.ServerPORT); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	stopScheduler()
+
+	// the server keeps running while draining, so verification codes and statuses still go through
+	gracePeriod := shutdownGracePeriod
+	if configApp.ShutdownGracePeriod > 0 {
+		gracePeriod = time.Duration(configApp.ShutdownGracePeriod) * time.Second
+	}
+	appLogger.Infow("shutting down, draining the running crawls", "running", cometScraperUC.Running(), "grace_period", gracePeriod.String())
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancelDrain()
+	if err := cometScraperUC.Shutdown(drainCtx); err != nil {
+		appLogger.Warnw("crawls still running were interrupted", "error", err)
+	}
+
+	serverCtx, cancelServer := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancelServer()
+	if err := e.Shutdown(serverCtx); err != nil {
+		appLogger.Errorw("could not shut the server down", "error", err)
+	}
 
-	e.Logger.Fatal(e.Start(":" + configApp.ServerPORT))
+	if err := dbInstance.Close(); err != nil {
+		appLogger.Errorw("could not close the database", "error", err)
+	}
+	if err := cacheInstance.Close(); err != nil {
+		appLogger.Errorw("could not close the cache", "error", err)
+	}
+	// flush the spans not exported yet
+	if err := shutdownTracing(context.Background()); err != nil {
+		appLogger.Errorw("could not flush the traces", "error", err)
+	}
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"cometScraper/infrastructure/datastore"
+)
+
+const migrateUsage = "usage: cometscraper migrate up|down|status"
+
+// runMigrate runs the migrate subcommand, returning the exit code
+func runMigrate(db *sql.DB, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := datastore.NewMigrator(context.Background(), db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "status":
+		var status datastore.MigrationStatus
+		if status, err = migrator.Status(); err == nil {
+			printMigrationStatus(status)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func printMigrationStatus(status datastore.MigrationStatus) {
+	if status.Version == 0 {
+		fmt.Println("version: none")
+	} else {
+		fmt.Printf("version: %d\n", status.Version)
+	}
+	if status.Dirty {
+		fmt.Println("dirty: the last migration failed midway and must be fixed by hand")
+	}
+
+	fmt.Printf("pending: %d\n", len(status.Pending))
+	for _, version := range status.Pending {
+		fmt.Printf("  %06d\n", version)
+	}
+}
+
+// autoMigrate applies the pending migrations before serving, other instances starting wait for the lock
+func autoMigrate(db *sql.DB) error {
+	migrator, err := datastore.NewMigrator(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"cometScraper/config"
+	"cometScraper/infrastructure/datastore"
+	pgsqlRepository "cometScraper/repository/pgsql"
+	"cometScraper/utils"
+)
+
+// Explodes the applicant JSONB of existing crawls into the candidate tables
+func main() {
+	batchSize := flag.Int("batch", 100, "crawls written per batch")
+	flag.Parse()
+
+	// Load config
+	configApp := config.LoadConfig()
+
+	// Setup infra
+	dbInstance, err := datastore.NewDatabase(configApp.DatabaseURL)
+	utils.PanicIfNeeded(err)
+
+	// Setup repository
+	candidateRepo := pgsqlRepository.NewPgsqlCandidateRepository(dbInstance)
+
+	total, err := candidateRepo.Backfill(context.Background(), *batchSize)
+	if err != nil {
+		log.Fatalf("backfill stopped after %d candidates: %v", total, err)
+	}
+
+	log.Printf("backfilled %d candidates", total)
+}
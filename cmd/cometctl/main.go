@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"cometScraper/config"
+	"cometScraper/entity"
+	"cometScraper/tools/scraper/pkg/applicant"
+	"cometScraper/tools/scraper/pkg/crawler"
+	"cometScraper/tools/scraper/pkg/element"
+	"cometScraper/utils/logger"
+	"golang.org/x/term"
+)
+
+// Crawls a single profile from the command line, without the API, Postgres or Redis
+func main() {
+	elementsPath := flag.String("elements", "tools/scraper/config/comet/input.json", "path of the elements config")
+	email := flag.String("email", "", "email of the Comet account, prompted when empty")
+	resumeUrl := flag.String("resume-url", "", "public resume link, crawled without logging in")
+	output := flag.String("o", "", "file the candidate is written to, stdout when empty")
+	logLevel := flag.String("log-level", "warn", "level of the crawler logs written to stderr")
+	flag.Parse()
+
+	if err := run(*elementsPath, *email, *resumeUrl, *output, *logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, "cometctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(elementsPath, email, resumeUrl, output, logLevel string) error {
+	elements, err := loadElements(elementsPath)
+	if err != nil {
+		return err
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	credentials := crawler.Credentials{ResumeUrl: resumeUrl}
+	codes := make(chan string, 1)
+	if resumeUrl == "" {
+		credentials.Email, credentials.Pass, err = promptCredentials(stdin, email)
+		if err != nil {
+			return err
+		}
+		credentials.Codes = codes
+	}
+
+	appLogger := logger.NewApiLoggerWithOutput(&config.Config{LoggerLevel: logLevel}, os.Stderr)
+	appLogger.InitLogger()
+
+	cometCrawler := crawler.NewCometCrawler(elements, nil, appLogger, crawler.DefaultStepPolicy, crawler.DefaultJobPolicy)
+
+	// ctrl-c stops the crawl and closes the browser
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cr := make(chan crawler.Response)
+	done := make(chan struct{})
+	go cometCrawler.StartCrawling(ctx, cometCrawler.GetUuid(), credentials, crawler.Checkpoint{}, cr, done)
+
+	var last crawler.Response
+	for finished := false; !finished; {
+		select {
+		case res := <-cr:
+			last = res
+			fmt.Fprintf(os.Stderr, "[%s] %s\n", res.TimeTaken, res.Status)
+			if res.Status == entity.AwaitingVerification {
+				code, err := prompt(stdin, "Verification code: ")
+				if err != nil {
+					return err
+				}
+				codes <- code
+			}
+		case <-done:
+			finished = true
+		}
+	}
+
+	if last.Status != entity.Success {
+		return fmt.Errorf("crawl ended with status %q", last.Status)
+	}
+
+	return writeCandidate(last.Applicant, output)
+}
+
+func loadElements(path string) (element.Elements, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return element.NewElement(file)
+}
+
+// promptCredentials asks for the email when it wasn't given and for the password, without echoing it
+func promptCredentials(stdin *bufio.Reader, email string) (string, string, error) {
+	var err error
+	if email == "" {
+		if email, err = prompt(stdin, "Email: "); err != nil {
+			return "", "", err
+		}
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", "", errors.New("the password can only be typed in a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", "", err
+	}
+
+	return email, string(password), nil
+}
+
+func prompt(stdin *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	line, err := stdin.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func writeCandidate(candidate applicant.Candidate, output string) error {
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(candidate)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,39 @@ type Config struct {
 	LoggerLevel    string
 	ContextTimeout int
 	Elements       element.Elements
+	// Export templates overriding the embedded ones, empty to keep the defaults
+	MarkdownTemplatePath string
+	HTMLTemplatePath     string
+	// Blob store of the profile pictures, "local" or "s3"
+	BlobStore    string
+	BlobLocalDir string
+	S3Endpoint   string
+	S3Bucket     string
+	S3Region     string
+	S3AccessKey  string
+	S3SecretKey  string
+	// Seconds an S3 request is given, zero keeps the default
+	S3Timeout int
+	// Crawl retries, zero keeps the crawler's defaults
+	CrawlMaxAttempts     int
+	CrawlStepMaxAttempts int
+	CrawlRetryBackoff    int
+	// Crawls an instance runs before reporting itself as not ready, zero for no limit
+	CrawlMaxRunning int
+	// Crawls of batches run at once, zero keeps the default
+	CrawlBatchWorkers int
+	// Seconds the running crawls are given to finish on shutdown, zero keeps the default
+	ShutdownGracePeriod int
+	// Key the credentials of the schedules are encrypted with, schedules are disabled without it
+	ScheduleSecret string
+	// Seconds between two polls of the due schedules, zero keeps the default
+	SchedulePollInterval int
+	// Apply the pending migrations on startup
+	AutoMigrate bool
+	// Field paths redacted from the logs on top of the default ones
+	SensitiveFields []string
+	// Exporter of the traces, "otlp" or "stdout", empty to disable tracing
+	TraceExporter string
 }
 
 // LoadConfig will load config from environment variable
@@ -28,8 +62,29 @@ func LoadConfig() (config *Config) {
 	databaseURL := os.Getenv("DATABASE_URL")
 	cacheURL := os.Getenv("CACHE_URL")
 	loggerLevel := os.Getenv("LOGGER_LEVEL")
+	sensitiveFields := strings.Split(os.Getenv("LOG_REDACT_FIELDS"), ",")
 	elementsInputPath := os.Getenv("ELEMENTS_INPUT_PATH")
 	contextTimeout, _ := strconv.Atoi(os.Getenv("CONTEXT_TIMEOUT"))
+	markdownTemplatePath := os.Getenv("EXPORT_MARKDOWN_TEMPLATE")
+	htmlTemplatePath := os.Getenv("EXPORT_HTML_TEMPLATE")
+	blobStore := os.Getenv("BLOB_STORE")
+	blobLocalDir := os.Getenv("BLOB_LOCAL_DIR")
+	s3Endpoint := os.Getenv("S3_ENDPOINT")
+	s3Bucket := os.Getenv("S3_BUCKET")
+	s3Region := os.Getenv("S3_REGION")
+	s3AccessKey := os.Getenv("S3_ACCESS_KEY")
+	s3SecretKey := os.Getenv("S3_SECRET_KEY")
+	s3Timeout, _ := strconv.Atoi(os.Getenv("S3_TIMEOUT"))
+	crawlMaxAttempts, _ := strconv.Atoi(os.Getenv("CRAWL_MAX_ATTEMPTS"))
+	crawlStepMaxAttempts, _ := strconv.Atoi(os.Getenv("CRAWL_STEP_MAX_ATTEMPTS"))
+	crawlRetryBackoff, _ := strconv.Atoi(os.Getenv("CRAWL_RETRY_BACKOFF"))
+	crawlMaxRunning, _ := strconv.Atoi(os.Getenv("CRAWL_MAX_RUNNING"))
+	crawlBatchWorkers, _ := strconv.Atoi(os.Getenv("CRAWL_BATCH_WORKERS"))
+	shutdownGracePeriod, _ := strconv.Atoi(os.Getenv("SHUTDOWN_GRACE_PERIOD"))
+	scheduleSecret := os.Getenv("SCHEDULE_SECRET")
+	schedulePollInterval, _ := strconv.Atoi(os.Getenv("SCHEDULE_POLL_INTERVAL"))
+	autoMigrate, _ := strconv.ParseBool(os.Getenv("AUTO_MIGRATE"))
+	traceExporter := os.Getenv("TRACE_EXPORTER")
 
 	fileContent, err := os.Open(elementsInputPath)
 
@@ -51,5 +106,30 @@ func LoadConfig() (config *Config) {
 		LoggerLevel:    loggerLevel,
 		ContextTimeout: contextTimeout,
 		Elements:       elements,
+
+		MarkdownTemplatePath: markdownTemplatePath,
+		HTMLTemplatePath:     htmlTemplatePath,
+
+		BlobStore:    blobStore,
+		BlobLocalDir: blobLocalDir,
+		S3Endpoint:   s3Endpoint,
+		S3Bucket:     s3Bucket,
+		S3Region:     s3Region,
+		S3AccessKey:  s3AccessKey,
+		S3SecretKey:  s3SecretKey,
+		S3Timeout:    s3Timeout,
+
+		CrawlMaxAttempts:     crawlMaxAttempts,
+		CrawlStepMaxAttempts: crawlStepMaxAttempts,
+		CrawlRetryBackoff:    crawlRetryBackoff,
+		CrawlMaxRunning:      crawlMaxRunning,
+		CrawlBatchWorkers:    crawlBatchWorkers,
+		ShutdownGracePeriod:  shutdownGracePeriod,
+		ScheduleSecret:       scheduleSecret,
+		SchedulePollInterval: schedulePollInterval,
+		AutoMigrate:          autoMigrate,
+
+		SensitiveFields: sensitiveFields,
+		TraceExporter:   traceExporter,
 	}
 }
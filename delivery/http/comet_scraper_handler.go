@@ -1,28 +1,51 @@
 package http
 
 import (
+	"cometScraper/entity"
 	"cometScraper/transport/request"
 	"cometScraper/usecase"
 	"cometScraper/utils"
+	"encoding/json"
+	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/labstack/echo/v4"
 	"net/http"
 )
 
+// streamFlushRows is how many rows are written between two flushes of a stream
+const streamFlushRows = 100
+
+// StreamingPaths are written progressively, so they must skip middlewares buffering the response
+var StreamingPaths = map[string]bool{
+	"/api/v1/comet/export.ndjson": true,
+}
+
 type CometScraperHandler struct {
 	CometScraperUC usecase.CometScraperUsecase
 }
 
-// NewCometScraperHandler will initialize the cometScrapers / resources endpoint
-func NewCometScraperHandler(e *echo.Echo, cometScraperUC usecase.CometScraperUsecase) {
+// NewCometScraperHandler will initialize the cometScrapers / resources endpoint, admin guards the debugging endpoints
+func NewCometScraperHandler(e *echo.Echo, cometScraperUC usecase.CometScraperUsecase, admin echo.MiddlewareFunc, m ...echo.MiddlewareFunc) {
 	handler := &CometScraperHandler{
 		CometScraperUC: cometScraperUC,
 	}
 
-	apiV1 := e.Group("/api/v1")
+	apiV1 := e.Group("/api/v1", m...)
 	apiV1.POST("/comet", handler.StartProcess)
+	apiV1.POST("/comet/batch", handler.StartBatch)
+	apiV1.GET("/comet/batch/:id", handler.GetBatch)
+	apiV1.GET("/comet/search", handler.Search)
+	apiV1.GET("/comet/export.ndjson", handler.ExportNDJSON)
 	apiV1.GET("/comet/:id", handler.GetByID)
+	apiV1.POST("/comet/:id/resume", handler.Resume)
+	apiV1.POST("/comet/:id/verification", handler.SubmitVerification)
+	apiV1.GET("/comet/:id/export", handler.Export)
+	apiV1.GET("/comet/:id/image", handler.GetImage)
+	apiV1.GET("/comet/:id/versions", handler.FetchVersions)
+	apiV1.GET("/comet/:id/artifacts", handler.FetchArtifacts, admin)
+	apiV1.GET("/comet/:id/artifacts/:name", handler.GetArtifact, admin)
 	apiV1.GET("/comet", handler.Fetch)
+	apiV1.GET("/candidates/:id/diff", handler.Diff)
 	apiV1.DELETE("/comet/:id", handler.Delete)
 }
 
@@ -53,6 +76,100 @@ func (h *CometScraperHandler) StartProcess(c echo.Context) error {
 	})
 }
 
+func (h *CometScraperHandler) StartBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.CreateBatchCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	uuid, err := h.CometScraperUC.StartBatch(ctx, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Batch Started",
+		"uuid":    uuid,
+	})
+}
+
+func (h *CometScraperHandler) GetBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	progress, err := h.CometScraperUC.GetBatch(ctx, id)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": progress})
+}
+
+func (h *CometScraperHandler) Resume(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+	var req request.CreateCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	if err := h.CometScraperUC.Resume(ctx, id, &req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Process Resumed",
+		"uuid":    id,
+	})
+}
+
+func (h *CometScraperHandler) SubmitVerification(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+	var req request.VerificationCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	if err := h.CometScraperUC.SubmitVerification(ctx, id, &req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Verification Code Submitted",
+		"uuid":    id,
+	})
+}
+
 func (h *CometScraperHandler) GetByID(c echo.Context) error {
 	ctx := c.Request().Context()
 	id := c.Param("id")
@@ -68,14 +185,205 @@ func (h *CometScraperHandler) GetByID(c echo.Context) error {
 
 func (h *CometScraperHandler) Fetch(c echo.Context) error {
 	ctx := c.Request().Context()
+	var req request.FetchCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	page, err := h.CometScraperUC.Fetch(ctx, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (h *CometScraperHandler) Search(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.SearchCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	results, err := h.CometScraperUC.Search(ctx, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": results})
+}
+
+func (h *CometScraperHandler) Export(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+	var req request.ExportCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	document, err := h.CometScraperUC.Export(ctx, id, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", id+"."+document.Extension))
+	return c.Blob(http.StatusOK, document.ContentType, document.Body)
+}
+
+func (h *CometScraperHandler) ExportNDJSON(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.StreamCometScraperReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	res := c.Response()
+	writeHeader := func() {
+		if !res.Committed {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.WriteHeader(http.StatusOK)
+		}
+	}
+
+	encoder := json.NewEncoder(res)
+	written := 0
+	err := h.CometScraperUC.Stream(ctx, &req, func(cometScraper entity.CometScraper) error {
+		writeHeader()
+		if err := encoder.Encode(cometScraper); err != nil {
+			return err
+		}
+
+		written++
+		if written%streamFlushRows == 0 {
+			res.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		c.Logger().Error(err)
+		// once rows are sent the status can't change, the client sees a truncated stream
+		if !res.Committed {
+			return c.JSON(utils.ParseHttpError(err))
+		}
+		return nil
+	}
+
+	writeHeader()
+	return nil
+}
+
+func (h *CometScraperHandler) GetImage(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	data, contentType, err := h.CometScraperUC.GetImage(ctx, id)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+func (h *CometScraperHandler) FetchVersions(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	versions, err := h.CometScraperUC.FetchVersions(ctx, id)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": versions})
+}
+
+func (h *CometScraperHandler) Diff(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+	var req request.DiffCandidateReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	diff, err := h.CometScraperUC.Diff(ctx, id, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": diff})
+}
+
+func (h *CometScraperHandler) FetchArtifacts(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	artifacts, err := h.CometScraperUC.FetchArtifacts(ctx, id)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": artifacts})
+}
+
+func (h *CometScraperHandler) GetArtifact(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+	name := c.Param("name")
 
-	cometScrapers, err := h.CometScraperUC.Fetch(ctx)
+	data, contentType, err := h.CometScraperUC.GetArtifact(ctx, id, name)
 	if err != nil {
 		c.Logger().Error(err)
 		return c.JSON(utils.ParseHttpError(err))
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"data": cometScrapers})
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	return c.Blob(http.StatusOK, contentType, data)
 }
 
 func (h *CometScraperHandler) Delete(c echo.Context) error {
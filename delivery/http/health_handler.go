@@ -0,0 +1,40 @@
+package http
+
+import (
+	"cometScraper/infrastructure/health"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type HealthHandler struct {
+	Liveness  *health.Checker
+	Readiness *health.Checker
+}
+
+// NewHealthHandler will initialize the probes, liveness tells whether the instance must be restarted
+// and readiness whether it can be sent traffic
+func NewHealthHandler(e *echo.Echo, liveness, readiness *health.Checker) {
+	handler := &HealthHandler{
+		Liveness:  liveness,
+		Readiness: readiness,
+	}
+
+	e.GET("/healthz", handler.Live)
+	e.GET("/readyz", handler.Ready)
+}
+
+func (h *HealthHandler) Live(c echo.Context) error {
+	return report(c, h.Liveness.Run(c.Request().Context()))
+}
+
+func (h *HealthHandler) Ready(c echo.Context) error {
+	return report(c, h.Readiness.Run(c.Request().Context()))
+}
+
+func report(c echo.Context, report health.Report) error {
+	if !report.Up() {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"cometScraper/transport/request"
+	"cometScraper/usecase"
+	"cometScraper/utils"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type ScheduleHandler struct {
+	ScheduleUC usecase.ScheduleUsecase
+}
+
+// NewScheduleHandler will initialize the schedules / resources endpoint
+func NewScheduleHandler(e *echo.Echo, scheduleUC usecase.ScheduleUsecase, m ...echo.MiddlewareFunc) {
+	handler := &ScheduleHandler{
+		ScheduleUC: scheduleUC,
+	}
+
+	apiV1 := e.Group("/api/v1", m...)
+	apiV1.POST("/schedules", handler.Create)
+	apiV1.GET("/schedules", handler.Fetch)
+	apiV1.GET("/schedules/:id", handler.GetByID)
+	apiV1.DELETE("/schedules/:id", handler.Delete)
+}
+
+func (h *ScheduleHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req request.CreateScheduleReq
+
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusUnprocessableEntity, utils.NewUnprocessableEntityError(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Logger().Error(err)
+		errVal := err.(validation.Errors)
+		return c.JSON(http.StatusBadRequest, utils.NewInvalidInputError(errVal))
+	}
+
+	schedule, err := h.ScheduleUC.Create(ctx, &req)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": schedule})
+}
+
+func (h *ScheduleHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	schedules, err := h.ScheduleUC.Fetch(ctx)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": schedules})
+}
+
+func (h *ScheduleHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	schedule, err := h.ScheduleUC.GetByID(ctx, id)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"data": schedule})
+}
+
+func (h *ScheduleHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	if err := h.ScheduleUC.Delete(ctx, id); err != nil {
+		c.Logger().Error(err)
+		return c.JSON(utils.ParseHttpError(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "schedule deleted",
+	})
+}
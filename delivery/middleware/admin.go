@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"cometScraper/entity"
+	"cometScraper/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAdmin will only let through requests the authentication layer flagged with the admin role
+func (m *Middleware) RequireAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(entity.RoleHeader) != entity.AdminRole {
+				return c.JSON(utils.ParseHttpError(utils.NewForbiddenError("admin only")))
+			}
+
+			return next(c)
+		}
+	}
+}
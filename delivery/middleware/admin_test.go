@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "cometScraper/delivery/middleware"
+	"cometScraper/entity"
+	"cometScraper/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(entity.RoleHeader, entity.AdminRole)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	h := appMiddleware.NewMiddleware(mockLogger).RequireAdmin()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequireAdminForbidden(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(entity.RoleHeader, "recruiter")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	h := appMiddleware.NewMiddleware(mockLogger).RequireAdmin()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// Logger will log every request once handled, with the sensitive fields of its query and body redacted
 func (m *Middleware) Logger() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -32,10 +33,10 @@ func (m *Middleware) Logger() echo.MiddlewareFunc {
 				"request_id", utils.GetReqID(req.Context()),
 				"remote_ip", c.RealIP(),
 				"host", req.Host,
-				"uri", req.RequestURI,
+				"uri", utils.Redact(req.RequestURI),
 				"method", req.Method,
 				"user_agent", req.UserAgent(),
-				"body", utils.CompactJSON(reqBody),
+				"body", utils.RedactJSON(reqBody),
 				"status", res.Status,
 				"latency", float64(time.Since(start).Nanoseconds()/1e4)/100.0,
 				"bytes_in", req.Header.Get(echo.HeaderContentLength),
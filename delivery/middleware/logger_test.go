@@ -0,0 +1,111 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appMiddleware "cometScraper/delivery/middleware"
+	"cometScraper/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// anyArgs matches a call made with n arguments, whatever they are
+func anyArgs(n int) []interface{} {
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+// loggedText flattens every argument the logger was called with
+func loggedText(logger *mocks.Logger) string {
+	var text strings.Builder
+	for _, call := range logger.Calls {
+		for _, arg := range call.Arguments {
+			fmt.Fprint(&text, arg, " ")
+		}
+	}
+	return text.String()
+}
+
+func TestLoggerRedactsPassword(t *testing.T) {
+	e := echo.New()
+	body := `{"email": "jane@doe.com", "password": "hunter2"}`
+	req := httptest.NewRequest(echo.POST, "/api/v1/comet", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var received map[string]string
+	handler := func(c echo.Context) error {
+		if err := c.Bind(&received); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Infow", anyArgs(23)...).Return()
+	h := appMiddleware.NewMiddleware(mockLogger).Logger()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	mockLogger.AssertNumberOfCalls(t, "Infow", 1)
+	assert.Equal(t, "hunter2", received["password"], "the handler still reads the password")
+	assert.Contains(t, loggedText(mockLogger), "jane@doe.com")
+	assert.NotContains(t, loggedText(mockLogger), "hunter2")
+}
+
+func TestLoggerRedactsQuery(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/comet?email=jane@doe.com&password=hunter2&token=abc123", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var received string
+	handler := func(c echo.Context) error {
+		received = c.QueryParam("password")
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Infow", anyArgs(23)...).Return()
+	h := appMiddleware.NewMiddleware(mockLogger).Logger()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	mockLogger.AssertNumberOfCalls(t, "Infow", 1)
+	assert.Equal(t, "hunter2", received, "the handler still reads the password")
+	assert.Contains(t, loggedText(mockLogger), "/api/v1/comet?email=jane@doe.com")
+	assert.NotContains(t, loggedText(mockLogger), "hunter2")
+	assert.NotContains(t, loggedText(mockLogger), "abc123")
+}
+
+func TestRecoverRedactsPanic(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/comet", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := func(c echo.Context) error {
+		panic(`could not log in with {"email":"jane@doe.com","password":"hunter2"}`)
+	}
+
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("Errorw", anyArgs(7)...).Return()
+	h := appMiddleware.NewMiddleware(mockLogger).Recover()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockLogger.AssertNumberOfCalls(t, "Errorw", 1)
+	assert.NotContains(t, loggedText(mockLogger), "hunter2")
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"cometScraper/infrastructure/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics will observe the duration of every request, by route so the histograms don't grow with the ids requested
+func (m *Middleware) Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			path := c.Path()
+			if path == "" {
+				path = "unmatched"
+			}
+			metrics.HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, path, strconv.Itoa(c.Response().Status)).
+				Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "cometScraper/delivery/middleware"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/comet/42", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/comet/:id")
+
+	handler := func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	before := testutil.CollectAndCount(metrics.HTTPRequestDuration)
+
+	mockLogger := new(mocks.Logger)
+	h := appMiddleware.NewMiddleware(mockLogger).Metrics()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, before+1, testutil.CollectAndCount(metrics.HTTPRequestDuration))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestDuration.WithLabelValues(echo.GET, "/comet/:id", "404").(prometheus.Histogram)))
+}
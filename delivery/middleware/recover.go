@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"cometScraper/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// recoverStackSize bounds the stack trace logged with a panic
+const recoverStackSize = 4 << 10
+
+// Recover will turn panics into internal server errors, logging them with their sensitive fields redacted
+func (m *Middleware) Recover() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				stack := make([]byte, recoverStackSize)
+				stack = stack[:runtime.Stack(stack, false)]
+				m.logger.Errorw("PANIC RECOVERED",
+					"request_id", utils.GetReqID(c.Request().Context()),
+					"panic", utils.Redact(r),
+					"stack", string(stack),
+				)
+
+				err = c.JSON(utils.ParseHttpError(fmt.Errorf("panic: %s", utils.Redact(r))))
+			}()
+
+			return next(c)
+		}
+	}
+}
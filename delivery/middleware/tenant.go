@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+
+	"cometScraper/entity"
+	"cometScraper/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// TenantID will read the tenant set by the authentication layer and put it into the context.
+// Requests without a tenant are rejected, so every query down the stack can be scoped by it.
+func (m *Middleware) TenantID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenantID := c.Request().Header.Get(entity.TenantIDHeader)
+			if tenantID == "" {
+				return c.JSON(utils.ParseHttpError(utils.NewUnauthorizedError("missing tenant")))
+			}
+
+			ctx := c.Request().Context()
+			newReq := c.Request().WithContext(context.WithValue(ctx, entity.TenantIDKey, tenantID))
+			c.SetRequest(newReq)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "cometScraper/delivery/middleware"
+	"cometScraper/entity"
+	"cometScraper/mocks"
+	"cometScraper/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(entity.TenantIDHeader, "tenant-a")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var tenantID string
+	handler := func(c echo.Context) error {
+		tenantID = utils.GetTenantID(c.Request().Context())
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	h := appMiddleware.NewMiddleware(mockLogger).TenantID()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", tenantID)
+}
+
+func TestTenantIDMissing(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	mockLogger := new(mocks.Logger)
+	h := appMiddleware.NewMiddleware(mockLogger).TenantID()(handler)
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appMiddleware "cometScraper/delivery/middleware"
+	"cometScraper/entity"
+	"cometScraper/infrastructure/tracing"
+	"cometScraper/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/comet/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/comet/:id")
+
+	var handlerSpan trace.SpanContext
+	handler := func(c echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(c.Request().Context())
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	mockLogger := new(mocks.Logger)
+	middleware := appMiddleware.NewMiddleware(mockLogger)
+	h := middleware.RequestID()(middleware.Tracing()(handler))
+	err := h(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /comet/:id", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, handlerSpan.SpanID(), span.SpanContext().SpanID())
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	var requestID string
+	for _, attr := range span.Attributes() {
+		if attr.Key == tracing.RequestID {
+			requestID = attr.Value.AsString()
+		}
+	}
+	assert.Equal(t, req.Header.Get(entity.RequestIDHeader), requestID)
+	assert.NotEmpty(t, requestID)
+}
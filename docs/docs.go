@@ -17,6 +17,46 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/healthz": {
+            "get": {
+                "description": "Check the instance is alive, so it is restarted when it isn't",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "503": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check Postgres, Redis, the browser, the elements config and the crawl queue, so traffic is only sent to ready instances",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "503": {
+                        "description": ""
+                    }
+                }
+            }
+        },
         "/api/v1/comet": {
             "get": {
                 "description": "Fetch CometScraper",
@@ -30,9 +70,64 @@ const docTemplate = `{
                     "CometScrapers"
                 ],
                 "summary": "Fetch CometScraper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 1 to 100, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only crawls with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only crawls created at or after this RFC3339 time",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only crawls created at or before this RFC3339 time",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "description": "sort column, default created_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "description": "sort order, default desc",
+                        "name": "sort_order",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": ""
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CometScraperPage"
+                        }
                     }
                 }
             },
@@ -66,6 +161,126 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/comet/batch": {
+            "post": {
+                "description": "Start a crawl per item, run a few at a time in the background, returns the batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Start a batch of CometScrapers",
+                "parameters": [
+                    {
+                        "description": "Credentials or resume links, 1 to 100 items",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateBatchCometScraperReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/batch/{id}": {
+            "get": {
+                "description": "Progress of a batch, counted by status, with the status of each of its crawls",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Get a batch of CometScrapers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/export.ndjson": {
+            "get": {
+                "description": "Stream every crawl as newline delimited JSON, oldest update first, for incremental syncs",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Export CometScrapers as NDJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only crawls updated after this RFC3339 time",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/search": {
+            "get": {
+                "description": "Full-text search over the scraped candidates, ranked and highlighted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Search CometScraper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "words to find in skills, role, experiences and description",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max results, 1 to 100, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
         "/api/v1/comet/{id}": {
             "get": {
                 "description": "Get CometScraper",
@@ -121,14 +336,444 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/api/v1/comet/{id}/export": {
+            "get": {
+                "description": "Export the scraped candidate as JSON Resume, Markdown, HTML or CSV",
+                "produces": [
+                    "application/json",
+                    "text/markdown",
+                    "text/html",
+                    "text/csv"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Export CometScraper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "jsonresume",
+                            "markdown",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "export format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/resume": {
+            "post": {
+                "description": "Crawl a failed CometScraper again, starting after the last stage it completed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Resume CometScraper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credentials to log in with",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateCometScraperReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/verification": {
+            "post": {
+                "description": "Submit the verification code Comet sent when the login was challenged, the process must be AWAITING_VERIFICATION",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Submit CometScraper verification code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verification code",
+                        "name": "verification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.VerificationCometScraperReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/artifacts": {
+            "get": {
+                "description": "List the screenshots and HTML snapshots captured when the crawl failed, admins only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "List CometScraper failure artifacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/artifacts/{name}": {
+            "get": {
+                "description": "Download a screenshot or HTML snapshot captured when the crawl failed, admins only",
+                "produces": [
+                    "image/png",
+                    "text/html"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Get CometScraper failure artifact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Artifact name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/image": {
+            "get": {
+                "description": "Get the profile picture downloaded while crawling",
+                "produces": [
+                    "image/*"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Get CometScraper image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/candidates/{id}/diff": {
+            "get": {
+                "description": "Role change, added, removed and changed skills and experiences between two versions of a candidate, the latest one and the one before by default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Diff Candidate versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version compared from, the one before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version compared to, the latest by default",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/comet/{id}/versions": {
+            "get": {
+                "description": "The candidates of each successful crawl of the person the scraper crawled, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CometScrapers"
+                ],
+                "summary": "Fetch CometScraper versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scraper id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/schedules": {
+            "post": {
+                "description": "Re-crawl a stored crawl on a cron expression, with credentials its owner consented to store encrypted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Create Schedule",
+                "parameters": [
+                    {
+                        "description": "Crawl, cron expression, credentials and consent",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateScheduleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            },
+            "get": {
+                "description": "The schedules of the tenant, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Fetch Schedules",
+                "parameters": [],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/api/v1/schedules/{id}": {
+            "get": {
+                "description": "Get a schedule with its next and last runs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Get Schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a schedule and the credentials it stored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Delete Schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "entity.CometScraperPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.CreateBatchCometScraperReq": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.CreateCometScraperReq"
+                    }
+                },
+            }
+        },
         "request.CreateCometScraperReq": {
             "type": "object",
             "properties": {
                 "email": { "type": "string"},
                 "password": { "type": "string"},
+                "resume_url": { "type": "string"},
+                "cookies": { "type": "array", "items": { "$ref": "#/definitions/request.SessionCookie"}},
+            }
+        },
+        "request.CreateScheduleReq": {
+            "type": "object",
+            "properties": {
+                "scraper_uuid": { "type": "string"},
+                "cron": { "type": "string"},
+                "email": { "type": "string"},
+                "password": { "type": "string"},
+                "resume_url": { "type": "string"},
+                "cookies": { "type": "array", "items": { "$ref": "#/definitions/request.SessionCookie"}},
+                "consent": { "type": "boolean"},
+            }
+        },
+        "request.SessionCookie": {
+            "type": "object",
+            "properties": {
+                "name": { "type": "string"},
+                "value": { "type": "string"},
+                "domain": { "type": "string"},
+                "path": { "type": "string"},
+                "secure": { "type": "boolean"},
+                "http_only": { "type": "boolean"},
+            }
+        },
+        "request.VerificationCometScraperReq": {
+            "type": "object",
+            "properties": {
+                "code": { "type": "string"}
             }
         }
     }
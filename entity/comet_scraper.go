@@ -2,14 +2,167 @@ package entity
 
 import (
 	"cometScraper/tools/scraper/pkg/applicant"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 )
 
 type CometScraper struct {
+	Uuid       string              `json:"uuid"`
+	TenantID   string              `json:"tenant_id"`
+	Status     string              `json:"status"`
+	Applicant  applicant.Candidate `json:"applicant"`
+	TimeTaken  string              `json:"time_taken"`
+	Attempts   int                 `json:"attempts"`
+	Checkpoint Checkpoint          `json:"-"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// Checkpoint is the last stage a crawl completed, with what the next stages need to go on from there.
+// The login is never checkpointed, the session doesn't outlive the browser it was opened in.
+type Checkpoint struct {
+	Stage       string `json:"stage,omitempty"`
+	ResumeUrl   string `json:"resume_url,omitempty"`
+	Skills      int    `json:"skills,omitempty"`
+	Experiences int    `json:"experiences,omitempty"`
+}
+
+// Value Make the Checkpoint struct implement the driver.Valuer interface. This method simply returns the JSON-encoded representation of the struct.
+func (c Checkpoint) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan Make the Checkpoint struct implement the sql.Scanner interface. This method simply decodes a JSON-encoded value into the struct fields.
+func (c *Checkpoint) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &c)
+}
+
+// CometScraperFilter narrows and orders a listing of crawls
+type CometScraperFilter struct {
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SortBy      string
+	SortOrder   string
+	Limit       int
+	After       *Cursor
+}
+
+// Cursor points at the last crawl of a page, by sort value and uuid
+type Cursor struct {
+	Value time.Time
+	Uuid  string
+}
+
+// CometScraperPage is one page of a crawls listing
+type CometScraperPage struct {
+	Data       []CometScraper `json:"data"`
+	NextCursor string         `json:"next_cursor"`
+	Total      int            `json:"total"`
+}
+
+// CometScraperSearchResult is a crawl matched by a full-text search
+type CometScraperSearchResult struct {
+	CometScraper
+	Rank      float64         `json:"rank"`
+	Highlight json.RawMessage `json:"highlight"`
+}
+
+// Artifact is a debugging capture stored when a crawl fails
+type Artifact struct {
+	Uuid        string    `json:"uuid"`
+	TenantID    string    `json:"tenant_id"`
+	Name        string    `json:"name"`
+	Step        string    `json:"step"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Batch is a set of crawls started by a single request
+type Batch struct {
+	Uuid      string    `json:"uuid"`
+	TenantID  string    `json:"tenant_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BatchItem is one crawl of a batch, in the position it was requested
+type BatchItem struct {
+	Position  int       `json:"position"`
+	Uuid      string    `json:"uuid"`
+	Status    string    `json:"status"`
+	TimeTaken string    `json:"time_taken"`
+	Attempts  int       `json:"attempts"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BatchProgress is the state of a batch's crawls, counted by status
+type BatchProgress struct {
+	Batch
+	Total     int            `json:"total"`
+	Finished  int            `json:"finished"`
+	Succeeded int            `json:"succeeded"`
+	Statuses  map[string]int `json:"statuses"`
+	Items     []BatchItem    `json:"items"`
+}
+
+// NewBatchProgress counts the items of a batch
+func NewBatchProgress(batch Batch, items []BatchItem) BatchProgress {
+	progress := BatchProgress{
+		Batch:    batch,
+		Total:    len(items),
+		Statuses: map[string]int{},
+		Items:    items,
+	}
+
+	for _, item := range items {
+		progress.Statuses[item.Status]++
+		if IsFinal(item.Status) {
+			progress.Finished++
+		}
+		if item.Status == Success {
+			progress.Succeeded++
+		}
+	}
+
+	return progress
+}
+
+// CometScraperVersion is the candidate of a successful crawl, numbered among the crawls of the same person
+type CometScraperVersion struct {
 	Uuid      string              `json:"uuid"`
-	Status    string              `json:"status"`
+	TenantID  string              `json:"tenant_id"`
+	PersonKey string              `json:"person_key"`
+	Version   int                 `json:"version"`
 	Applicant applicant.Candidate `json:"applicant"`
 	TimeTaken string              `json:"time_taken"`
+	CrawledAt time.Time           `json:"crawled_at"`
 	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Schedule re-crawls a stored crawl on a cron expression, with the credentials its owner consented to store
+type Schedule struct {
+	Uuid        string     `json:"uuid"`
+	TenantID    string     `json:"tenant_id"`
+	ScraperUuid string     `json:"scraper_uuid"`
+	Cron        string     `json:"cron"`
+	Credentials string     `json:"-"`
+	NextRunAt   time.Time  `json:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	LastError   string     `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CandidateDiff is what changed in a candidate between two versions of its crawl
+type CandidateDiff struct {
+	Uuid string `json:"uuid"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	applicant.Diff
 }
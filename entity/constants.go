@@ -1,17 +1,80 @@
 package entity
 
+import "time"
+
 type ctxKeyRequestID int
 
+type ctxKeyTenantID int
+
 const RequestIDKey ctxKeyRequestID = 0
 
+const TenantIDKey ctxKeyTenantID = 0
+
+type ctxKeyProcessUuid int
+
+type ctxKeyStage int
+
+// ProcessUuidKey holds the UUID of the crawl a context runs for
+const ProcessUuidKey ctxKeyProcessUuid = 0
+
+// StageKey holds the step of the crawl a context runs
+const StageKey ctxKeyStage = 0
+
 var RequestIDHeader = "X-Request-Id"
 
+// TenantIDHeader is set by the authentication layer in front of the API
+var TenantIDHeader = "X-Tenant-Id"
+
+// RoleHeader is set by the authentication layer in front of the API
+var RoleHeader = "X-Role"
+
+const AdminRole = "admin"
+
 const (
-	Start             string = "PROCESS STARTED, WILL LOGIN"
-	FailedCredentials        = "WRONG CREDENTIALS"
-	Logged                   = "LOGGED SUCCESSFULLY, GOING TO CRAWL THE BASIC DATA"
-	Fail                     = "INTERNAL ERROR, CONTACT ADMIN"
-	Basic                    = "CRAWLED BASIC DATA, STARTING TO CRAWL EXPERIENCES AND SKILLS"
-	Success                  = "SUCCESS"
-	TimeOut                  = "THE OPERATION TOOK LONGER THAN EXPECTED, PLEASE TRY AGAIN"
+	Queued               string = "QUEUED"
+	Start                       = "PROCESS STARTED, WILL LOGIN"
+	FailedCredentials           = "WRONG CREDENTIALS"
+	AwaitingVerification        = "AWAITING_VERIFICATION"
+	FailedVerification          = "WRONG VERIFICATION CODE"
+	Logged                      = "LOGGED SUCCESSFULLY, GOING TO CRAWL THE BASIC DATA"
+	Fail                        = "INTERNAL ERROR, CONTACT ADMIN"
+	Retrying                    = "TRANSIENT ERROR, RETRYING"
+	Basic                       = "CRAWLED BASIC DATA, STARTING TO CRAWL EXPERIENCES AND SKILLS"
+	Success                     = "SUCCESS"
+	TimeOut                     = "THE OPERATION TOOK LONGER THAN EXPECTED, PLEASE TRY AGAIN"
+	Interrupted                 = "INTERRUPTED"
 )
+
+// Crawl steps, naming the artifacts captured when one fails
+const (
+	StepLogin                string = "login"
+	StepBaseInfo                    = "base_info"
+	StepSkillsAndExperiences        = "skills_and_experiences"
+)
+
+const (
+	SortByCreatedAt string = "created_at"
+	SortByUpdatedAt        = "updated_at"
+	SortOrderAsc           = "asc"
+	SortOrderDesc          = "desc"
+)
+
+const (
+	DefaultFetchLimit = 20
+	MaxFetchLimit     = 100
+	MaxSearchLength   = 256
+	MaxBatchSize      = 100
+	MaxSchedules      = 100
+)
+
+// IsFinal reports whether a crawl stopped with status, an interrupted crawl being stopped until it is resumed
+func IsFinal(status string) bool {
+	switch status {
+	case Success, Fail, FailedCredentials, FailedVerification, TimeOut, Interrupted:
+		return true
+	}
+	return false
+}
+
+// MinScheduleInterval is the shortest interval between two runs of a schedule
+const MinScheduleInterval = time.Hour
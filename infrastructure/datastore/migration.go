@@ -0,0 +1,100 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+
+	"cometScraper/migration"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// MigrationStatus tells which migration the database is at and which ones are left to apply
+type MigrationStatus struct {
+	Version uint
+	// Dirty is set when the last migration failed midway, it has to be fixed by hand
+	Dirty   bool
+	Pending []uint
+}
+
+// Migrator applies the migrations embedded in the binary. It keeps the schema_migrations table of the migrate CLI,
+// and holds a Postgres advisory lock while migrating so instances starting together don't race.
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// NewMigrator will create a Migrator on a connection of db, closing it leaves db open
+func NewMigrator(ctx context.Context, db *sql.DB) (*Migrator, error) {
+	src, err := iofs.New(migration.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+
+	return &Migrator{migrate: m, source: src}, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down rolls the last applied migration back
+func (m *Migrator) Down() error {
+	return m.migrate.Steps(-1)
+}
+
+// Status reports the version of the database and the migrations left to apply
+func (m *Migrator) Status() (status MigrationStatus, err error) {
+	status.Version, status.Dirty, err = m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return
+	}
+	err = nil
+
+	version, err := m.source.First()
+	for err == nil {
+		if version > status.Version {
+			status.Pending = append(status.Pending, version)
+		}
+		version, err = m.source.Next(version)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+
+	return
+}
+
+// Close releases the connection and the advisory lock
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	if dbErr != nil {
+		return dbErr
+	}
+	return sourceErr
+}
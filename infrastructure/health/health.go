@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cometScraper/tools/scraper/pkg/element"
+	"cometScraper/utils"
+	"github.com/go-redis/redis"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports whether a dependency works by returning a nil error
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a single check
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is up when every check is
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Up reports whether every check passed
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs its checks concurrently, each given at most timeout
+type Checker struct {
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+// NewChecker will create a Checker without any check, its report is up until some are added
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+// Add registers a check under the name it is reported with
+func (c *Checker) Add(name string, check Check) *Checker {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	return c
+}
+
+// Run runs every check, the report is down when one of them failed or timed out
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(c.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			result := c.run(ctx, nc.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(nc)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("no answer after %s", c.timeout)
+	}
+
+	result := Result{Status: StatusUp, Duration: time.Since(start).String()}
+	if err != nil {
+		// errors may quote a connection string
+		result.Status, result.Error = StatusDown, utils.Redact(err)
+	}
+
+	return result
+}
+
+// Postgres checks the database answers
+func Postgres(db *sql.DB) Check {
+	return db.PingContext
+}
+
+// Redis checks the cache answers
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		return client.WithContext(ctx).Ping().Err()
+	}
+}
+
+// Elements checks the selectors the crawler relies on were loaded
+func Elements(elements element.Elements) Check {
+	return func(context.Context) error {
+		if elements == nil || elements.GetUrls().StartPage == "" {
+			return errors.New("elements config is not loaded")
+		}
+		return nil
+	}
+}
+
+// Queue checks the crawls running leave room for another one, max being zero for no limit
+func Queue(running func() int, max int) Check {
+	return func(context.Context) error {
+		if n := running(); max > 0 && n >= max {
+			return fmt.Errorf("%d crawls running, the limit is %d", n, max)
+		}
+		return nil
+	}
+}
+
+// Draining checks the instance isn't shutting down, so no new crawl is sent to it
+func Draining(draining func() bool) Check {
+	return func(context.Context) error {
+		if draining() {
+			return errors.New("shutting down")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cometScraper/infrastructure/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerRun(t *testing.T) {
+	t.Run("up when every check passes", func(t *testing.T) {
+		report := health.NewChecker(time.Second).
+			Add("postgres", func(context.Context) error { return nil }).
+			Add("queue", health.Queue(func() int { return 1 }, 2)).
+			Run(context.Background())
+
+		assert.True(t, report.Up())
+		assert.Equal(t, health.StatusUp, report.Checks["postgres"].Status)
+		assert.Equal(t, health.StatusUp, report.Checks["queue"].Status)
+	})
+
+	t.Run("down when a check fails, with its redacted error", func(t *testing.T) {
+		report := health.NewChecker(time.Second).
+			Add("postgres", func(context.Context) error { return nil }).
+			Add("redis", func(context.Context) error { return errors.New("dial redis password=hunter2: refused") }).
+			Run(context.Background())
+
+		assert.False(t, report.Up())
+		assert.Equal(t, health.StatusUp, report.Checks["postgres"].Status)
+		assert.Equal(t, health.StatusDown, report.Checks["redis"].Status)
+		assert.NotContains(t, report.Checks["redis"].Error, "hunter2")
+	})
+
+	t.Run("down when a check doesn't answer in time", func(t *testing.T) {
+		report := health.NewChecker(10*time.Millisecond).
+			Add("browser", func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}).
+			Run(context.Background())
+
+		assert.False(t, report.Up())
+		assert.Equal(t, "no answer after 10ms", report.Checks["browser"].Error)
+	})
+
+	t.Run("down when the queue is saturated", func(t *testing.T) {
+		report := health.NewChecker(time.Second).
+			Add("queue", health.Queue(func() int { return 3 }, 3)).
+			Run(context.Background())
+
+		assert.False(t, report.Up())
+		assert.Equal(t, "3 crawls running, the limit is 3", report.Checks["queue"].Error)
+	})
+	t.Run("down when the instance is shutting down", func(t *testing.T) {
+		report := health.NewChecker(time.Second).
+			Add("shutdown", health.Draining(func() bool { return true })).
+			Run(context.Background())
+
+		assert.False(t, report.Up())
+		assert.Equal(t, "shutting down", report.Checks["shutdown"].Error)
+	})
+}
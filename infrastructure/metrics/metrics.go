@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"time"
+
+	"cometScraper/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "comet"
+
+var (
+	// HTTPRequestDuration is observed by the metrics middleware, path is the route, not the requested URI
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+
+	crawlsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "crawls_total",
+		Help:      "Crawls that ended, by terminal status.",
+	}, []string{"status"})
+
+	crawlStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "crawl_stage_duration_seconds",
+		Help:      "Duration of the stages of a crawl, retries included.",
+		Buckets:   []float64{1, 2.5, 5, 10, 20, 30, 45, 60, 90, 120},
+	}, []string{"stage"})
+
+	// ActiveBrowsers counts the headless browsers currently open
+	ActiveBrowsers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_browsers",
+		Help:      "Headless browsers currently open.",
+	})
+
+	// CrawlsInFlight counts the crawls started and not finished yet
+	CrawlsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "crawls_in_flight",
+		Help:      "Crawls started and not finished yet, whether waiting on a browser, a retry or a verification code.",
+	})
+
+	// CrawlQueueDepth counts the crawls of batches waiting for a slot to start
+	CrawlQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "crawl_queue_depth",
+		Help:      "Crawls of batches queued and waiting for a slot to start.",
+	})
+
+	datastoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "datastore_duration_seconds",
+		Help:      "Duration of the calls to the datastores by store and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"store", "operation"})
+)
+
+// terminalStatuses labels the statuses a crawl ends with
+var terminalStatuses = map[string]string{
+	entity.Success:            "success",
+	entity.Fail:               "fail",
+	entity.FailedCredentials:  "wrong_credentials",
+	entity.FailedVerification: "wrong_verification_code",
+	entity.TimeOut:            "timeout",
+	entity.Interrupted:        "interrupted",
+}
+
+// ObserveCrawl counts a crawl reaching status, statuses a crawl goes on from are ignored
+func ObserveCrawl(status string) {
+	if label, ok := terminalStatuses[status]; ok {
+		crawlsTotal.WithLabelValues(label).Inc()
+	}
+}
+
+// ObserveStage records how long a stage of a crawl took since it started
+func ObserveStage(stage string, start time.Time) {
+	crawlStageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDatastore returns the function recording how long a datastore call took, to be deferred:
+//
+//	defer metrics.ObserveDatastore("postgres", "get_by_id")()
+func ObserveDatastore(store, operation string) func() {
+	start := time.Now()
+	return func() {
+		datastoreDuration.WithLabelValues(store, operation).Observe(time.Since(start).Seconds())
+	}
+}
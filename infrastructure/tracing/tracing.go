@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"cometScraper/entity"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName names the service in the exported traces, unless OTEL_SERVICE_NAME is set
+	ServiceName = "comet-scraper"
+	tracerName  = "cometScraper"
+)
+
+// Attributes linking the spans to a crawl and to the request that started it
+const (
+	ProcessUuid = attribute.Key("comet.process_uuid")
+	RequestID   = attribute.Key("comet.request_id")
+	TenantID    = attribute.Key("comet.tenant_id")
+)
+
+// Init installs the global tracer provider for the exporter, "otlp" sends the spans to the collector set by
+// OTEL_EXPORTER_OTLP_ENDPOINT, "stdout" prints them and an empty exporter keeps tracing disabled.
+// The returned function flushes the spans left before the app exits.
+func Init(ctx context.Context, exporter string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		spanExporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceNameKey.String(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span, tagged with the tenant and the request ID found in ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, opts...)
+	if tenantID, ok := ctx.Value(entity.TenantIDKey).(string); ok && tenantID != "" {
+		span.SetAttributes(TenantID.String(tenantID))
+	}
+	if requestID, ok := ctx.Value(entity.RequestIDKey).(string); ok && requestID != "" {
+		span.SetAttributes(RequestID.String(requestID))
+	}
+
+	return ctx, span
+}
+
+// End ends a span, marking it as failed when err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach returns a context outliving ctx, for work a request starts in the background.
+// It keeps the span, so the work stays in the request's trace, the tenant and the request ID.
+func Detach(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	if tenantID := ctx.Value(entity.TenantIDKey); tenantID != nil {
+		detached = context.WithValue(detached, entity.TenantIDKey, tenantID)
+	}
+	if requestID := ctx.Value(entity.RequestIDKey); requestID != nil {
+		detached = context.WithValue(detached, entity.RequestIDKey, requestID)
+	}
+
+	return detached
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS comet_scraper_tenant_id_idx;
+
+ALTER TABLE comet_scraper DROP COLUMN IF EXISTS tenant_id;
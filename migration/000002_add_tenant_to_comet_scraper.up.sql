@@ -0,0 +1,3 @@
+ALTER TABLE comet_scraper ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS comet_scraper_tenant_id_idx ON comet_scraper (tenant_id);
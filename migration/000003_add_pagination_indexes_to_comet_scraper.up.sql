@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS comet_scraper_tenant_created_at_idx ON comet_scraper (tenant_id, created_at, uuid);
+CREATE INDEX IF NOT EXISTS comet_scraper_tenant_updated_at_idx ON comet_scraper (tenant_id, updated_at, uuid);
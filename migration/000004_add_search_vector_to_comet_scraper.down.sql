@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS comet_scraper_search_vector_idx;
+
+ALTER TABLE comet_scraper DROP COLUMN IF EXISTS search_vector;
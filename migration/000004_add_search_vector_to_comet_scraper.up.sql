@@ -0,0 +1,10 @@
+-- applicant.name is encrypted at rest, so only plain fields are indexed
+ALTER TABLE comet_scraper ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(applicant->>'role', '')), 'A') ||
+    setweight(jsonb_to_tsvector('simple', jsonb_path_query_array(coalesce(applicant, '{}'), '$.skill[*].name'), '["string"]'), 'A') ||
+    setweight(jsonb_to_tsvector('simple', jsonb_path_query_array(coalesce(applicant, '{}'), '$.experience[*].title'), '["string"]'), 'B') ||
+    setweight(jsonb_to_tsvector('simple', jsonb_path_query_array(coalesce(applicant, '{}'), '$.experience[*].desc'), '["string"]'), 'C') ||
+    setweight(to_tsvector('simple', coalesce(applicant->>'description', '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS comet_scraper_search_vector_idx ON comet_scraper USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS candidate_experiences;
+DROP TABLE IF EXISTS candidate_skills;
+DROP TABLE IF EXISTS candidates;
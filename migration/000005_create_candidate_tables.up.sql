@@ -0,0 +1,38 @@
+-- candidates.name keeps the encrypted value stored in comet_scraper.applicant
+CREATE TABLE IF NOT EXISTS candidates (
+    uuid VARCHAR PRIMARY KEY,
+    tenant_id VARCHAR NOT NULL DEFAULT '',
+    image_url VARCHAR,
+    name VARCHAR,
+    role VARCHAR,
+    description TEXT,
+    time_of_experience VARCHAR,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS candidates_tenant_id_idx ON candidates (tenant_id);
+
+CREATE TABLE IF NOT EXISTS candidate_skills (
+    id BIGSERIAL PRIMARY KEY,
+    candidate_uuid VARCHAR NOT NULL REFERENCES candidates (uuid) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name VARCHAR,
+    time VARCHAR
+);
+
+CREATE INDEX IF NOT EXISTS candidate_skills_candidate_uuid_idx ON candidate_skills (candidate_uuid);
+CREATE INDEX IF NOT EXISTS candidate_skills_name_idx ON candidate_skills (lower(name));
+
+CREATE TABLE IF NOT EXISTS candidate_experiences (
+    id BIGSERIAL PRIMARY KEY,
+    candidate_uuid VARCHAR NOT NULL REFERENCES candidates (uuid) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title VARCHAR,
+    skill VARCHAR,
+    description TEXT,
+    period VARCHAR,
+    period_count VARCHAR
+);
+
+CREATE INDEX IF NOT EXISTS candidate_experiences_candidate_uuid_idx ON candidate_experiences (candidate_uuid);
@@ -0,0 +1,7 @@
+ALTER TABLE candidate_experiences DROP COLUMN IF EXISTS months;
+ALTER TABLE candidate_experiences DROP COLUMN IF EXISTS end_date;
+ALTER TABLE candidate_experiences DROP COLUMN IF EXISTS start_date;
+
+ALTER TABLE candidate_skills DROP COLUMN IF EXISTS months;
+
+ALTER TABLE candidates DROP COLUMN IF EXISTS years_of_experience;
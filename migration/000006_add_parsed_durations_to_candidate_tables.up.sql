@@ -0,0 +1,7 @@
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS years_of_experience NUMERIC(5, 2);
+
+ALTER TABLE candidate_skills ADD COLUMN IF NOT EXISTS months INTEGER;
+
+ALTER TABLE candidate_experiences ADD COLUMN IF NOT EXISTS start_date DATE;
+ALTER TABLE candidate_experiences ADD COLUMN IF NOT EXISTS end_date DATE;
+ALTER TABLE candidate_experiences ADD COLUMN IF NOT EXISTS months INTEGER;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS comet_scraper_artifacts;
@@ -0,0 +1,12 @@
+-- the artifacts themselves live in the blob store, this only indexes them
+CREATE TABLE IF NOT EXISTS comet_scraper_artifacts (
+    uuid VARCHAR NOT NULL,
+    tenant_id VARCHAR NOT NULL DEFAULT '',
+    name VARCHAR NOT NULL,
+    step VARCHAR NOT NULL,
+    content_type VARCHAR NOT NULL,
+    created_at TIMESTAMP,
+    PRIMARY KEY (uuid, name)
+);
+
+CREATE INDEX IF NOT EXISTS comet_scraper_artifacts_tenant_id_uuid_idx ON comet_scraper_artifacts (tenant_id, uuid);
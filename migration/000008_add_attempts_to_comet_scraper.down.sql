@@ -0,0 +1 @@
+ALTER TABLE comet_scraper DROP COLUMN IF EXISTS attempts;
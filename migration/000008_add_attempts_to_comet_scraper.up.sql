@@ -0,0 +1 @@
+ALTER TABLE comet_scraper ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE comet_scraper ADD COLUMN IF NOT EXISTS checkpoint JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS comet_scraper_created_at_idx;
+DROP INDEX IF EXISTS comet_scraper_status_idx;
+
+ALTER TABLE comet_scraper DROP CONSTRAINT IF EXISTS comet_scraper_pkey;
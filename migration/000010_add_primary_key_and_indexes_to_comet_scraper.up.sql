@@ -0,0 +1,5 @@
+-- fails on duplicated uuids, they have to be cleaned up by hand first
+ALTER TABLE comet_scraper ADD CONSTRAINT comet_scraper_pkey PRIMARY KEY (uuid);
+
+CREATE INDEX IF NOT EXISTS comet_scraper_status_idx ON comet_scraper (status);
+CREATE INDEX IF NOT EXISTS comet_scraper_created_at_idx ON comet_scraper (created_at);
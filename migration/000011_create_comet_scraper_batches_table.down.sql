@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS comet_scraper_batch_items;
+DROP TABLE IF EXISTS comet_scraper_batches;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS comet_scraper_batches (
+    uuid VARCHAR PRIMARY KEY,
+    tenant_id VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP
+);
+
+-- a deleted crawl leaves its batch
+CREATE TABLE IF NOT EXISTS comet_scraper_batch_items (
+    batch_uuid VARCHAR NOT NULL REFERENCES comet_scraper_batches (uuid) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    uuid VARCHAR NOT NULL REFERENCES comet_scraper (uuid) ON DELETE CASCADE,
+    PRIMARY KEY (batch_uuid, position)
+);
+
+CREATE INDEX IF NOT EXISTS comet_scraper_batches_tenant_id_uuid_idx ON comet_scraper_batches (tenant_id, uuid);
+CREATE INDEX IF NOT EXISTS comet_scraper_batch_items_uuid_idx ON comet_scraper_batch_items (uuid);
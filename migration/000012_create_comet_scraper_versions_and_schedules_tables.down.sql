@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS comet_scraper_schedules;
+DROP TABLE IF EXISTS comet_scraper_versions;
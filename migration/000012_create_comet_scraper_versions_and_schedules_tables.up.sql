@@ -0,0 +1,27 @@
+-- the candidates a crawl produced before each re-crawl, the latest one stays in comet_scraper
+CREATE TABLE IF NOT EXISTS comet_scraper_versions (
+    uuid VARCHAR NOT NULL REFERENCES comet_scraper (uuid) ON DELETE CASCADE,
+    tenant_id VARCHAR NOT NULL DEFAULT '',
+    version INTEGER NOT NULL,
+    applicant JSONB,
+    time_taken VARCHAR,
+    crawled_at TIMESTAMP,
+    created_at TIMESTAMP,
+    PRIMARY KEY (uuid, version)
+);
+
+-- credentials holds the consented credentials, encrypted with SCHEDULE_SECRET
+CREATE TABLE IF NOT EXISTS comet_scraper_schedules (
+    uuid VARCHAR PRIMARY KEY,
+    tenant_id VARCHAR NOT NULL DEFAULT '',
+    scraper_uuid VARCHAR NOT NULL REFERENCES comet_scraper (uuid) ON DELETE CASCADE,
+    cron VARCHAR NOT NULL,
+    credentials VARCHAR NOT NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    last_run_at TIMESTAMP,
+    last_error VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comet_scraper_schedules_tenant_id_idx ON comet_scraper_schedules (tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS comet_scraper_schedules_next_run_at_idx ON comet_scraper_schedules (next_run_at);
@@ -0,0 +1,2 @@
+DELETE FROM comet_scraper_versions WHERE backfilled;
+ALTER TABLE comet_scraper_versions DROP COLUMN IF EXISTS backfilled;
@@ -0,0 +1,11 @@
+-- keeps the candidate of the successful crawls that aren't versioned yet, their last crawl being in comet_scraper only,
+-- the rows inserted being marked so rolling back removes them only
+ALTER TABLE comet_scraper_versions ADD COLUMN IF NOT EXISTS backfilled BOOLEAN NOT NULL DEFAULT FALSE;
+
+INSERT INTO comet_scraper_versions (uuid, tenant_id, version, applicant, time_taken, crawled_at, created_at, backfilled)
+SELECT c.uuid, c.tenant_id,
+    (SELECT COALESCE(MAX(v.version), 0) + 1 FROM comet_scraper_versions v WHERE v.uuid = c.uuid),
+    c.applicant, c.time_taken, c.updated_at, NOW(), TRUE
+FROM comet_scraper c
+WHERE c.status = 'SUCCESS' AND c.applicant IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM comet_scraper_versions v WHERE v.uuid = c.uuid AND v.crawled_at >= c.updated_at);
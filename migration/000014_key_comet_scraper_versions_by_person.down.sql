@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS comet_scraper_versions_uuid_idx;
+ALTER TABLE comet_scraper_versions DROP CONSTRAINT IF EXISTS comet_scraper_versions_pkey;
+
+UPDATE comet_scraper_versions v
+SET version = n.version
+FROM (
+    SELECT tenant_id, person_key, version AS old_version,
+        ROW_NUMBER() OVER (PARTITION BY uuid ORDER BY crawled_at, version) AS version
+    FROM comet_scraper_versions
+) n
+WHERE v.tenant_id = n.tenant_id AND v.person_key = n.person_key AND v.version = n.old_version;
+
+ALTER TABLE comet_scraper_versions ADD CONSTRAINT comet_scraper_versions_pkey PRIMARY KEY (uuid, version);
+ALTER TABLE comet_scraper_versions DROP COLUMN IF EXISTS person_key;
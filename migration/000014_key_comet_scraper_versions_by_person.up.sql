@@ -0,0 +1,27 @@
+-- versions are numbered per person, the profile url of their crawls hashed as the stored crawls do,
+-- or per crawl when it has none
+ALTER TABLE comet_scraper_versions ADD COLUMN IF NOT EXISTS person_key VARCHAR NOT NULL DEFAULT '';
+
+-- normalized as personKey does: ascii whitespace trimmed, ascii letters lowered, fragment, query and trailing slashes dropped
+UPDATE comet_scraper_versions v
+SET person_key = COALESCE(
+    (SELECT encode(sha256(convert_to(n.url, 'UTF8')), 'hex')
+     FROM comet_scraper c,
+        LATERAL (SELECT rtrim(split_part(split_part(translate(btrim(c.checkpoint->>'resume_url', E' \t\n\r\f\v'),
+            'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz'), '#', 1), '?', 1), '/') AS url) n
+     WHERE c.uuid = v.uuid AND n.url <> ''),
+    v.uuid);
+
+ALTER TABLE comet_scraper_versions DROP CONSTRAINT IF EXISTS comet_scraper_versions_pkey;
+
+UPDATE comet_scraper_versions v
+SET version = n.version
+FROM (
+    SELECT uuid, version AS old_version,
+        ROW_NUMBER() OVER (PARTITION BY tenant_id, person_key ORDER BY crawled_at, uuid, version) AS version
+    FROM comet_scraper_versions
+) n
+WHERE v.uuid = n.uuid AND v.version = n.old_version;
+
+ALTER TABLE comet_scraper_versions ADD CONSTRAINT comet_scraper_versions_pkey PRIMARY KEY (tenant_id, person_key, version);
+CREATE INDEX IF NOT EXISTS comet_scraper_versions_uuid_idx ON comet_scraper_versions (uuid);
@@ -0,0 +1,11 @@
+UPDATE comet_scraper
+SET applicant = jsonb_set(applicant, '{name}', to_jsonb(substr(applicant->>'name', 5)))
+WHERE applicant->>'name' LIKE 'enc:%';
+
+UPDATE comet_scraper_versions
+SET applicant = jsonb_set(applicant, '{name}', to_jsonb(substr(applicant->>'name', 5)))
+WHERE applicant->>'name' LIKE 'enc:%';
+
+UPDATE candidates
+SET name = substr(name, 5)
+WHERE name LIKE 'enc:%';
@@ -0,0 +1,15 @@
+-- candidate names are encrypted with their crawl's uuid and stored base64 url encoded, except those the first update
+-- of older crawls saved in plaintext. The encrypted ones are marked with the prefix the usecase now writes, so plaintext
+-- names are told apart without trying to decrypt them. A ciphertext holds at least the 16 bytes of its IV, at least
+-- 24 base64 characters without a space, which a plaintext name would hardly look like.
+UPDATE comet_scraper
+SET applicant = jsonb_set(applicant, '{name}', to_jsonb('enc:' || (applicant->>'name')))
+WHERE applicant->>'name' ~ '^[A-Za-z0-9_-]+={0,2}$' AND length(applicant->>'name') >= 24 AND length(applicant->>'name') % 4 = 0;
+
+UPDATE comet_scraper_versions
+SET applicant = jsonb_set(applicant, '{name}', to_jsonb('enc:' || (applicant->>'name')))
+WHERE applicant->>'name' ~ '^[A-Za-z0-9_-]+={0,2}$' AND length(applicant->>'name') >= 24 AND length(applicant->>'name') % 4 = 0;
+
+UPDATE candidates
+SET name = 'enc:' || name
+WHERE name ~ '^[A-Za-z0-9_-]+={0,2}$' AND length(name) >= 24 AND length(name) % 4 = 0;
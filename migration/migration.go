@@ -0,0 +1,9 @@
+// Package migration embeds the SQL migrations, so the binary can apply them without the migrate CLI
+package migration
+
+import "embed"
+
+// FS holds the up and down migrations, named as golang-migrate expects them
+//
+//go:embed *.sql
+var FS embed.FS
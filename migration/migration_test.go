@@ -0,0 +1,35 @@
+package migration_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"cometScraper/migration"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	_, err := iofs.New(migration.FS, ".")
+	require.NoError(t, err)
+
+	names, err := fs.Glob(migration.FS, "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		files[name] = true
+	}
+
+	for _, name := range names {
+		if strings.HasSuffix(name, ".up.sql") {
+			assert.True(t, files[strings.TrimSuffix(name, ".up.sql")+".down.sql"], "%s has no down migration", name)
+		}
+		if strings.HasSuffix(name, ".down.sql") {
+			assert.True(t, files[strings.TrimSuffix(name, ".down.sql")+".up.sql"], "%s has no up migration", name)
+		}
+	}
+}
@@ -3,8 +3,8 @@
 package mocks
 
 import (
-	"cometScraper/tools/scraper/app/applicant"
-	"cometScraper/tools/scraper/app/element"
+	"cometScraper/tools/scraper/pkg/applicant"
+	"cometScraper/tools/scraper/pkg/element"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -2,15 +2,21 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	crawler "cometScraper/tools/scraper/pkg/crawler"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // CometScraper is an autogenerated mock type for the CometScraper type
 type CometScraper struct {
 	mock.Mock
 }
 
-// StartCrawling provides a mock function with given fields:
-func (_m *CometScraper) StartCrawling() string {
+// GetUuid provides a mock function with given fields:
+func (_m *CometScraper) GetUuid() string {
 	ret := _m.Called()
 
 	var r0 string
@@ -23,6 +29,11 @@ func (_m *CometScraper) StartCrawling() string {
 	return r0
 }
 
+// StartCrawling provides a mock function with given fields: ctx, id, credentials, checkpoint, cr, done
+func (_m *CometScraper) StartCrawling(ctx context.Context, id string, credentials crawler.Credentials, checkpoint crawler.Checkpoint, cr chan crawler.Response, done chan struct{}) {
+	_m.Called(ctx, id, credentials, checkpoint, cr, done)
+}
+
 type mockConstructorTestingTNewCometScraper interface {
 	mock.TestingT
 	Cleanup(func())
@@ -7,6 +7,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // CometScraperRepository is an autogenerated mock type for the CometScraperRepository type
@@ -28,13 +30,13 @@ func (_m *CometScraperRepository) Create(ctx context.Context, cometScraper *enti
 	return r0
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *CometScraperRepository) Delete(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, tenantID, id
+func (_m *CometScraperRepository) Delete(ctx context.Context, tenantID string, id string) error {
+	ret := _m.Called(ctx, tenantID, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, tenantID, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -42,13 +44,34 @@ func (_m *CometScraperRepository) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx
-func (_m *CometScraperRepository) Fetch(ctx context.Context) ([]entity.CometScraper, error) {
-	ret := _m.Called(ctx)
+// Count provides a mock function with given fields: ctx, tenantID, filter
+func (_m *CometScraperRepository) Count(ctx context.Context, tenantID string, filter entity.CometScraperFilter) (int, error) {
+	ret := _m.Called(ctx, tenantID, filter)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CometScraperFilter) int); ok {
+		r0 = rf(ctx, tenantID, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.CometScraperFilter) error); ok {
+		r1 = rf(ctx, tenantID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, tenantID, filter
+func (_m *CometScraperRepository) Fetch(ctx context.Context, tenantID string, filter entity.CometScraperFilter) ([]entity.CometScraper, error) {
+	ret := _m.Called(ctx, tenantID, filter)
 
 	var r0 []entity.CometScraper
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.CometScraper); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CometScraperFilter) []entity.CometScraper); ok {
+		r0 = rf(ctx, tenantID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.CometScraper)
@@ -56,8 +79,8 @@ func (_m *CometScraperRepository) Fetch(ctx context.Context) ([]entity.CometScra
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.CometScraperFilter) error); ok {
+		r1 = rf(ctx, tenantID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,20 +88,43 @@ func (_m *CometScraperRepository) Fetch(ctx context.Context) ([]entity.CometScra
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *CometScraperRepository) GetByID(ctx context.Context, id string) (entity.CometScraper, error) {
-	ret := _m.Called(ctx, id)
+// GetByID provides a mock function with given fields: ctx, tenantID, id
+func (_m *CometScraperRepository) GetByID(ctx context.Context, tenantID string, id string) (entity.CometScraper, error) {
+	ret := _m.Called(ctx, tenantID, id)
 
 	var r0 entity.CometScraper
-	if rf, ok := ret.Get(0).(func(context.Context, string) entity.CometScraper); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) entity.CometScraper); ok {
+		r0 = rf(ctx, tenantID, id)
 	} else {
 		r0 = ret.Get(0).(entity.CometScraper)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, tenantID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, tenantID, q, limit
+func (_m *CometScraperRepository) Search(ctx context.Context, tenantID string, q string, limit int) ([]entity.CometScraperSearchResult, error) {
+	ret := _m.Called(ctx, tenantID, q, limit)
+
+	var r0 []entity.CometScraperSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []entity.CometScraperSearchResult); ok {
+		r0 = rf(ctx, tenantID, q, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CometScraperSearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, tenantID, q, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,6 +132,20 @@ func (_m *CometScraperRepository) GetByID(ctx context.Context, id string) (entit
 	return r0, r1
 }
 
+// Stream provides a mock function with given fields: ctx, tenantID, since, fn
+func (_m *CometScraperRepository) Stream(ctx context.Context, tenantID string, since *time.Time, fn func(entity.CometScraper) error) error {
+	ret := _m.Called(ctx, tenantID, since, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, func(entity.CometScraper) error) error); ok {
+		r0 = rf(ctx, tenantID, since, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, c
 func (_m *CometScraperRepository) Update(ctx context.Context, c *entity.CometScraper) error {
 	ret := _m.Called(ctx, c)
@@ -100,6 +160,20 @@ func (_m *CometScraperRepository) Update(ctx context.Context, c *entity.CometScr
 	return r0
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, comet
+func (_m *CometScraperRepository) UpdateStatus(ctx context.Context, comet *entity.CometScraper) error {
+	ret := _m.Called(ctx, comet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CometScraper) error); ok {
+		r0 = rf(ctx, comet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewCometScraperRepository interface {
 	mock.TestingT
 	Cleanup(func())
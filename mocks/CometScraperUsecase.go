@@ -6,6 +6,8 @@ import (
 	entity "cometScraper/entity"
 	context "context"
 
+	export "cometScraper/transport/export"
+
 	mock "github.com/stretchr/testify/mock"
 
 	request "cometScraper/transport/request"
@@ -44,22 +46,173 @@ func (_m *CometScraperUsecase) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx
-func (_m *CometScraperUsecase) Fetch(ctx context.Context) ([]entity.CometScraper, error) {
-	ret := _m.Called(ctx)
+// Diff provides a mock function with given fields: ctx, id, _a2
+func (_m *CometScraperUsecase) Diff(ctx context.Context, id string, _a2 *request.DiffCandidateReq) (entity.CandidateDiff, error) {
+	ret := _m.Called(ctx, id, _a2)
 
-	var r0 []entity.CometScraper
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.CometScraper); ok {
-		r0 = rf(ctx)
+	var r0 entity.CandidateDiff
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.DiffCandidateReq) entity.CandidateDiff); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Get(0).(entity.CandidateDiff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.DiffCandidateReq) error); ok {
+		r1 = rf(ctx, id, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Draining provides a mock function with given fields:
+func (_m *CometScraperUsecase) Draining() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Export provides a mock function with given fields: ctx, id, _a2
+func (_m *CometScraperUsecase) Export(ctx context.Context, id string, _a2 *request.ExportCometScraperReq) (export.Document, error) {
+	ret := _m.Called(ctx, id, _a2)
+
+	var r0 export.Document
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.ExportCometScraperReq) export.Document); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Get(0).(export.Document)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.ExportCometScraperReq) error); ok {
+		r1 = rf(ctx, id, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchArtifacts provides a mock function with given fields: ctx, id
+func (_m *CometScraperUsecase) FetchArtifacts(ctx context.Context, id string) ([]entity.Artifact, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []entity.Artifact
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.Artifact); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.CometScraper)
+			r0 = ret.Get(0).([]entity.Artifact)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchVersions provides a mock function with given fields: ctx, id
+func (_m *CometScraperUsecase) FetchVersions(ctx context.Context, id string) ([]entity.CometScraperVersion, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []entity.CometScraperVersion
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CometScraperVersion); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CometScraperVersion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, _a1
+func (_m *CometScraperUsecase) Fetch(ctx context.Context, _a1 *request.FetchCometScraperReq) (entity.CometScraperPage, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 entity.CometScraperPage
+	if rf, ok := ret.Get(0).(func(context.Context, *request.FetchCometScraperReq) entity.CometScraperPage); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(entity.CometScraperPage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *request.FetchCometScraperReq) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetArtifact provides a mock function with given fields: ctx, id, name
+func (_m *CometScraperUsecase) GetArtifact(ctx context.Context, id string, name string) ([]byte, string, error) {
+	ret := _m.Called(ctx, id, name)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []byte); ok {
+		r0 = rf(ctx, id, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) string); ok {
+		r1 = rf(ctx, id, name)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, id, name)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBatch provides a mock function with given fields: ctx, id
+func (_m *CometScraperUsecase) GetBatch(ctx context.Context, id string) (entity.BatchProgress, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.BatchProgress
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.BatchProgress); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.BatchProgress)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,6 +241,136 @@ func (_m *CometScraperUsecase) GetByID(ctx context.Context, id string) (entity.C
 	return r0, r1
 }
 
+// GetImage provides a mock function with given fields: ctx, id
+func (_m *CometScraperUsecase) GetImage(ctx context.Context, id string) ([]byte, string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Recrawl provides a mock function with given fields: ctx, id, _a2
+func (_m *CometScraperUsecase) Recrawl(ctx context.Context, id string, _a2 *request.CreateCometScraperReq) error {
+	ret := _m.Called(ctx, id, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.CreateCometScraperReq) error); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields: ctx, id, _a2
+func (_m *CometScraperUsecase) Resume(ctx context.Context, id string, _a2 *request.CreateCometScraperReq) error {
+	ret := _m.Called(ctx, id, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.CreateCometScraperReq) error); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Running provides a mock function with given fields:
+func (_m *CometScraperUsecase) Running() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, _a1
+func (_m *CometScraperUsecase) Search(ctx context.Context, _a1 *request.SearchCometScraperReq) ([]entity.CometScraperSearchResult, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 []entity.CometScraperSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, *request.SearchCometScraperReq) []entity.CometScraperSearchResult); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CometScraperSearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *request.SearchCometScraperReq) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Shutdown provides a mock function with given fields: ctx
+func (_m *CometScraperUsecase) Shutdown(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartBatch provides a mock function with given fields: ctx, _a1
+func (_m *CometScraperUsecase) StartBatch(ctx context.Context, _a1 *request.CreateBatchCometScraperReq) (string, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *request.CreateBatchCometScraperReq) string); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *request.CreateBatchCometScraperReq) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartProcess provides a mock function with given fields: ctx, _a1
 func (_m *CometScraperUsecase) StartProcess(ctx context.Context, _a1 *request.CreateCometScraperReq) (string, error) {
 	ret := _m.Called(ctx, _a1)
@@ -109,13 +392,55 @@ func (_m *CometScraperUsecase) StartProcess(ctx context.Context, _a1 *request.Cr
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, cometScraper
-func (_m *CometScraperUsecase) Update(ctx context.Context, id string, cometScraper *entity.CometScraper) error {
-	ret := _m.Called(ctx, id, cometScraper)
+// Stream provides a mock function with given fields: ctx, _a1, fn
+func (_m *CometScraperUsecase) Stream(ctx context.Context, _a1 *request.StreamCometScraperReq, fn func(entity.CometScraper) error) error {
+	ret := _m.Called(ctx, _a1, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *request.StreamCometScraperReq, func(entity.CometScraper) error) error); ok {
+		r0 = rf(ctx, _a1, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmitVerification provides a mock function with given fields: ctx, id, _a2
+func (_m *CometScraperUsecase) SubmitVerification(ctx context.Context, id string, _a2 *request.VerificationCometScraperReq) error {
+	ret := _m.Called(ctx, id, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.VerificationCometScraperReq) error); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, cometScraper
+func (_m *CometScraperUsecase) Update(ctx context.Context, cometScraper *entity.CometScraper) error {
+	ret := _m.Called(ctx, cometScraper)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CometScraper) error); ok {
+		r0 = rf(ctx, cometScraper)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertStatus provides a mock function with given fields: ctx, id, status
+func (_m *CometScraperUsecase) UpsertStatus(ctx context.Context, id string, status string) error {
+	ret := _m.Called(ctx, id, status)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *entity.CometScraper) error); ok {
-		r0 = rf(ctx, id, cometScraper)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, status)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,7 +3,7 @@
 package mocks
 
 import (
-	"cometScraper/tools/scraper/app/element"
+	"cometScraper/tools/scraper/pkg/element"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -2,7 +2,11 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	logger "cometScraper/utils/logger"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Logger is an autogenerated mock type for the Logger type
 type Logger struct {
@@ -175,6 +179,24 @@ func (_m *Logger) Warnw(msg string, keysAndValues ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// With provides a mock function with given fields: keysAndValues
+func (_m *Logger) With(keysAndValues ...interface{}) logger.Logger {
+	var _ca []interface{}
+	_ca = append(_ca, keysAndValues...)
+	ret := _m.Called(_ca...)
+
+	var r0 logger.Logger
+	if rf, ok := ret.Get(0).(func(...interface{}) logger.Logger); ok {
+		r0 = rf(keysAndValues...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.Logger)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewLogger interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobRepository represent the blob store repositories
+type BlobRepository interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type localBlobRepository struct {
+	dir string
+}
+
+// NewLocalBlobRepository will create an object that represent the BlobRepository interface, storing blobs under dir
+func NewLocalBlobRepository(dir string) BlobRepository {
+	return &localBlobRepository{
+		dir: dir,
+	}
+}
+
+// path keeps keys inside the store directory
+func (r *localBlobRepository) path(key string) (string, error) {
+	path := filepath.Join(r.dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(filepath.Clean(r.dir), path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", errors.New("invalid blob key")
+	}
+	return path, nil
+}
+
+// Put write the blob, replacing the previous one
+func (r *localBlobRepository) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o640)
+}
+
+// Get read the blob, its content type is sniffed from the data
+func (r *localBlobRepository) Get(ctx context.Context, key string) ([]byte, string, error) {
+	path, err := r.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, http.DetectContentType(data), nil
+}
+
+// Delete remove the blob, missing blobs are ignored
+func (r *localBlobRepository) Delete(ctx context.Context, key string) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
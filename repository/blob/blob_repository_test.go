@@ -0,0 +1,111 @@
+package blob_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	blobRepo "cometScraper/repository/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// SetupS3 starts a minimal S3 stand-in keeping objects in memory, served under path
+func SetupS3(t *testing.T, path string) blobRepo.BlobRepository {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	contentTypes := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.EscapedPath(), path+"/images/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/eu-west-3/s3/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path] = body
+			contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", contentTypes[r.URL.Path])
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return blobRepo.NewS3BlobRepository(blobRepo.S3Config{
+		Endpoint:  server.URL + path,
+		Bucket:    "images",
+		Region:    "eu-west-3",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}, server.Client())
+}
+
+func TestBlobRepositories(t *testing.T) {
+	repositories := map[string]blobRepo.BlobRepository{
+		"local":           blobRepo.NewLocalBlobRepository(t.TempDir()),
+		"s3":              SetupS3(t, ""),
+		"s3 under a path": SetupS3(t, "/storage"),
+	}
+
+	for name, repository := range repositories {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := "tenant a+b/8e2f0a1c-uuid"
+
+			_, _, err := repository.Get(ctx, key)
+			assert.Equal(t, blobRepo.ErrNotFound, err)
+
+			require.NoError(t, repository.Put(ctx, key, png, "image/png"))
+
+			data, contentType, err := repository.Get(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, png, data)
+			assert.Equal(t, "image/png", contentType)
+
+			require.NoError(t, repository.Delete(ctx, key))
+			require.NoError(t, repository.Delete(ctx, key))
+
+			_, _, err = repository.Get(ctx, key)
+			assert.Equal(t, blobRepo.ErrNotFound, err)
+		})
+	}
+}
+
+func TestLocalBlobRepositoryRejectsEscapingKeys(t *testing.T) {
+	repository := blobRepo.NewLocalBlobRepository(t.TempDir())
+	err := repository.Put(context.Background(), "../outside", png, "image/png")
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultS3Timeout bounds each S3 request when the config doesn't
+const defaultS3Timeout = 30 * time.Second
+
+// S3Config holds what is needed to reach an S3 compatible API, buckets are addressed path-style
+// under the endpoint's path
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// Timeout of each request when no client is given, zero keeps the default
+	Timeout time.Duration
+}
+
+type s3BlobRepository struct {
+	config S3Config
+	client *http.Client
+}
+
+// NewS3BlobRepository will create an object that represent the BlobRepository interface, storing blobs in an S3 bucket
+func NewS3BlobRepository(config S3Config, client *http.Client) BlobRepository {
+	if client == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = defaultS3Timeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	return &s3BlobRepository{
+		config: config,
+		client: client,
+	}
+}
+
+// Put upload the blob, replacing the previous one
+func (r *s3BlobRepository) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := r.newRequest(ctx, http.MethodPut, key, data, contentType)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return checkResponse(res)
+}
+
+// Get download the blob with the content type it was stored with
+func (r *s3BlobRepository) Get(ctx context.Context, key string) ([]byte, string, error) {
+	req, err := r.newRequest(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if err = checkResponse(res); err != nil {
+		return nil, "", err
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, res.Header.Get("Content-Type"), nil
+}
+
+// Delete remove the blob, missing blobs are ignored
+func (r *s3BlobRepository) Delete(ctx context.Context, key string) error {
+	req, err := r.newRequest(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err = checkResponse(res); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func checkResponse(res *http.Response) error {
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("s3 responded %d: %s", res.StatusCode, body)
+	}
+
+	return nil
+}
+
+// newRequest build a request signed with AWS Signature Version 4
+func (r *s3BlobRepository) newRequest(ctx context.Context, method, key string, data []byte, contentType string) (*http.Request, error) {
+	endpoint, err := url.Parse(r.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimRight(endpoint.Path, "/")
+	endpoint.Path = prefix + "/" + r.config.Bucket + "/" + key
+	endpoint.RawPath = uriEncode(prefix) + "/" + uriEncode(r.config.Bucket) + "/" + uriEncode(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(data)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		endpoint.RawPath,
+		"",
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + r.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+r.config.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, r.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.config.AccessKey, scope, signedHeaders, signature,
+	))
+
+	return req, nil
+}
+
+// uriEncode escape every byte but the unreserved ones and '/', as S3 expects in paths
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,29 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// withTx runs fn inside a transaction, committing only when it succeeds
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return
+	}
+
+	return tx.Commit()
+}
+
+// spanError is the error a repository call's span reports, a missing row being an expected outcome
+func spanError(err error) error {
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package pgsql
+
+import (
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"context"
+	"database/sql"
+)
+
+// ArtifactRepository represent the crawl failure artifact's repository contract
+type ArtifactRepository interface {
+	Create(ctx context.Context, artifact *entity.Artifact) error
+	FetchByScraper(ctx context.Context, tenantID, id string) ([]entity.Artifact, error)
+	GetByName(ctx context.Context, tenantID, id, name string) (entity.Artifact, error)
+}
+
+type pgsqlArtifactRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlArtifactRepository will create new an artifactRepository object representation of ArtifactRepository interface
+func NewPgsqlArtifactRepository(db *sql.DB) ArtifactRepository {
+	return &pgsqlArtifactRepository{
+		db: db,
+	}
+}
+
+// Create records an artifact, a new capture of the same name replaces the previous one
+func (r *pgsqlArtifactRepository) Create(ctx context.Context, artifact *entity.Artifact) (err error) {
+	defer metrics.ObserveDatastore("postgres", "artifact.create")()
+	ctx, span := tracing.Start(ctx, "postgres.artifact.create")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `INSERT INTO comet_scraper_artifacts (uuid, tenant_id, name, step, content_type, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (uuid, name) DO UPDATE SET step = EXCLUDED.step, content_type = EXCLUDED.content_type, created_at = EXCLUDED.created_at`
+	_, err = r.db.ExecContext(ctx, query, artifact.Uuid, artifact.TenantID, artifact.Name, artifact.Step, artifact.ContentType, artifact.CreatedAt)
+	return
+}
+
+func (r *pgsqlArtifactRepository) FetchByScraper(ctx context.Context, tenantID, id string) (artifacts []entity.Artifact, err error) {
+	defer metrics.ObserveDatastore("postgres", "artifact.fetch_by_scraper")()
+	ctx, span := tracing.Start(ctx, "postgres.artifact.fetch_by_scraper")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `SELECT uuid, tenant_id, name, step, content_type, created_at FROM comet_scraper_artifacts
+		WHERE uuid = $1 AND tenant_id = $2 ORDER BY created_at, name`
+	rows, err := r.db.QueryContext(ctx, query, id, tenantID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	artifacts = []entity.Artifact{}
+	for rows.Next() {
+		var artifact entity.Artifact
+		err = rows.Scan(&artifact.Uuid, &artifact.TenantID, &artifact.Name, &artifact.Step, &artifact.ContentType, &artifact.CreatedAt)
+		if err != nil {
+			return
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	err = rows.Err()
+	return
+}
+
+func (r *pgsqlArtifactRepository) GetByName(ctx context.Context, tenantID, id, name string) (artifact entity.Artifact, err error) {
+	defer metrics.ObserveDatastore("postgres", "artifact.get_by_name")()
+	ctx, span := tracing.Start(ctx, "postgres.artifact.get_by_name")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `SELECT uuid, tenant_id, name, step, content_type, created_at FROM comet_scraper_artifacts
+		WHERE uuid = $1 AND tenant_id = $2 AND name = $3`
+	err = r.db.QueryRowContext(ctx, query, id, tenantID, name).Scan(&artifact.Uuid, &artifact.TenantID, &artifact.Name, &artifact.Step, &artifact.ContentType, &artifact.CreatedAt)
+	return
+}
+
+// deleteArtifacts removes the records of a crawl's artifacts, which don't cascade from it
+func deleteArtifacts(ctx context.Context, tx *sql.Tx, tenantID, id string) (err error) {
+	_, err = tx.ExecContext(ctx, "DELETE FROM comet_scraper_artifacts WHERE uuid = $1 AND tenant_id = $2", id, tenantID)
+	return
+}
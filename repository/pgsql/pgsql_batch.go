@@ -0,0 +1,89 @@
+package pgsql
+
+import (
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"context"
+	"database/sql"
+)
+
+// BatchRepository represent the crawl batch's repository contract
+type BatchRepository interface {
+	Create(ctx context.Context, batch *entity.Batch, ids []string) error
+	GetByID(ctx context.Context, tenantID, id string) (entity.Batch, error)
+	FetchItems(ctx context.Context, tenantID, id string) ([]entity.BatchItem, error)
+}
+
+type pgsqlBatchRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlBatchRepository will create new a batchRepository object representation of BatchRepository interface
+func NewPgsqlBatchRepository(db *sql.DB) BatchRepository {
+	return &pgsqlBatchRepository{
+		db: db,
+	}
+}
+
+// Create records a batch with its crawls, ids being in the order they were requested
+func (r *pgsqlBatchRepository) Create(ctx context.Context, batch *entity.Batch, ids []string) (err error) {
+	defer metrics.ObserveDatastore("postgres", "batch.create")()
+	ctx, span := tracing.Start(ctx, "postgres.batch.create")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO comet_scraper_batches (uuid, tenant_id, created_at) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, query, batch.Uuid, batch.TenantID, batch.CreatedAt); err != nil {
+			return err
+		}
+
+		for position, id := range ids {
+			query = "INSERT INTO comet_scraper_batch_items (batch_uuid, position, uuid) VALUES ($1, $2, $3)"
+			if _, err := tx.ExecContext(ctx, query, batch.Uuid, position, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *pgsqlBatchRepository) GetByID(ctx context.Context, tenantID, id string) (batch entity.Batch, err error) {
+	defer metrics.ObserveDatastore("postgres", "batch.get_by_id")()
+	ctx, span := tracing.Start(ctx, "postgres.batch.get_by_id")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "SELECT uuid, tenant_id, created_at FROM comet_scraper_batches WHERE uuid = $1 AND tenant_id = $2"
+	err = r.db.QueryRowContext(ctx, query, id, tenantID).Scan(&batch.Uuid, &batch.TenantID, &batch.CreatedAt)
+	return
+}
+
+// FetchItems returns the current state of a batch's crawls, by position
+func (r *pgsqlBatchRepository) FetchItems(ctx context.Context, tenantID, id string) (items []entity.BatchItem, err error) {
+	defer metrics.ObserveDatastore("postgres", "batch.fetch_items")()
+	ctx, span := tracing.Start(ctx, "postgres.batch.fetch_items")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `SELECT i.position, c.uuid, c.status, c.time_taken, c.attempts, c.updated_at FROM comet_scraper_batch_items i
+		JOIN comet_scraper c ON c.uuid = i.uuid
+		WHERE i.batch_uuid = $1 AND c.tenant_id = $2 ORDER BY i.position`
+	rows, err := r.db.QueryContext(ctx, query, id, tenantID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	items = []entity.BatchItem{}
+	for rows.Next() {
+		var item entity.BatchItem
+		err = rows.Scan(&item.Position, &item.Uuid, &item.Status, &item.TimeTaken, &item.Attempts, &item.UpdatedAt)
+		if err != nil {
+			return
+		}
+		items = append(items, item)
+	}
+
+	err = rows.Err()
+	return
+}
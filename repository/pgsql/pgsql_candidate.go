@@ -0,0 +1,124 @@
+package pgsql
+
+import (
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"context"
+	"database/sql"
+)
+
+// CandidateRepository represent the normalized candidate's repository contract
+type CandidateRepository interface {
+	Backfill(ctx context.Context, batchSize int) (int, error)
+}
+
+type pgsqlCandidateRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlCandidateRepository will create new an candidateRepository object representation of CandidateRepository interface
+func NewPgsqlCandidateRepository(db *sql.DB) CandidateRepository {
+	return &pgsqlCandidateRepository{
+		db: db,
+	}
+}
+
+// saveCandidate replaces the normalized rows of the crawl's applicant
+func saveCandidate(ctx context.Context, tx *sql.Tx, comet *entity.CometScraper) (err error) {
+	candidate := comet.Applicant
+	query := `INSERT INTO candidates (uuid, tenant_id, image_url, name, role, description, time_of_experience, years_of_experience, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (uuid) DO UPDATE SET image_url = EXCLUDED.image_url, name = EXCLUDED.name, role = EXCLUDED.role,
+		description = EXCLUDED.description, time_of_experience = EXCLUDED.time_of_experience,
+		years_of_experience = EXCLUDED.years_of_experience, updated_at = EXCLUDED.updated_at`
+	_, err = tx.ExecContext(ctx, query, comet.Uuid, comet.TenantID, candidate.ImageUrl, candidate.Name, candidate.Role, candidate.Description, candidate.TimeOfExperience, candidate.YearsOfExperience, comet.CreatedAt, comet.UpdatedAt)
+	if err != nil {
+		return
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM candidate_skills WHERE candidate_uuid = $1", comet.Uuid); err != nil {
+		return
+	}
+
+	for position, skill := range candidate.Skill {
+		query = "INSERT INTO candidate_skills (candidate_uuid, position, name, time, months) VALUES ($1, $2, $3, $4, $5)"
+		if _, err = tx.ExecContext(ctx, query, comet.Uuid, position, skill.Name, skill.Time, skill.Months); err != nil {
+			return
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM candidate_experiences WHERE candidate_uuid = $1", comet.Uuid); err != nil {
+		return
+	}
+
+	for position, job := range candidate.Experience {
+		query = `INSERT INTO candidate_experiences (candidate_uuid, position, title, skill, description, period, period_count, start_date, end_date, months)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		if _, err = tx.ExecContext(ctx, query, comet.Uuid, position, job.Title, job.Skill, job.Desc, job.Period, job.PeriodCount, job.Start, job.End, job.Months); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// deleteCandidate removes the crawl's candidate, skills and experiences cascade
+func deleteCandidate(ctx context.Context, tx *sql.Tx, tenantID, id string) (err error) {
+	_, err = tx.ExecContext(ctx, "DELETE FROM candidates WHERE uuid = $1 AND tenant_id = $2", id, tenantID)
+	return
+}
+
+// Backfill explodes the applicant JSONB of every crawl into the candidate tables, batchSize crawls at a time
+func (r *pgsqlCandidateRepository) Backfill(ctx context.Context, batchSize int) (total int, err error) {
+	defer metrics.ObserveDatastore("postgres", "candidate.backfill")()
+	ctx, span := tracing.Start(ctx, "postgres.candidate.backfill")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	lastUuid := ""
+	for {
+		var batch []entity.CometScraper
+		batch, err = r.fetchBatch(ctx, lastUuid, batchSize)
+		if err != nil || len(batch) == 0 {
+			return
+		}
+
+		for i := range batch {
+			comet := &batch[i]
+			// crawls stored before the duration parser existed lack the typed fields
+			comet.Applicant.Clear()
+			err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+				return saveCandidate(ctx, tx, comet)
+			})
+			if err != nil {
+				return
+			}
+			total++
+		}
+
+		lastUuid = batch[len(batch)-1].Uuid
+	}
+}
+
+func (r *pgsqlCandidateRepository) fetchBatch(ctx context.Context, afterUuid string, limit int) (cometScrapers []entity.CometScraper, err error) {
+	query := `SELECT uuid, tenant_id, applicant, created_at, updated_at FROM comet_scraper
+		WHERE applicant IS NOT NULL AND uuid > $1 ORDER BY uuid LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, afterUuid, limit)
+	if err != nil {
+		return cometScrapers, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var cometScraper entity.CometScraper
+		err := rows.Scan(&cometScraper.Uuid, &cometScraper.TenantID, &cometScraper.Applicant, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
+		if err != nil {
+			return cometScrapers, err
+		}
+
+		cometScrapers = append(cometScrapers, cometScraper)
+	}
+
+	return cometScrapers, rows.Err()
+}
@@ -2,19 +2,26 @@ package pgsql
 
 import (
 	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // CometScraperRepository represent the cometScraper's repository contract
 type CometScraperRepository interface {
 	Create(ctx context.Context, cometScraper *entity.CometScraper) error
-	GetByID(ctx context.Context, id string) (entity.CometScraper, error)
-	Fetch(ctx context.Context) ([]entity.CometScraper, error)
+	GetByID(ctx context.Context, tenantID, id string) (entity.CometScraper, error)
+	Fetch(ctx context.Context, tenantID string, filter entity.CometScraperFilter) ([]entity.CometScraper, error)
+	Count(ctx context.Context, tenantID string, filter entity.CometScraperFilter) (int, error)
+	Search(ctx context.Context, tenantID, q string, limit int) ([]entity.CometScraperSearchResult, error)
+	Stream(ctx context.Context, tenantID string, since *time.Time, fn func(entity.CometScraper) error) error
 	Update(ctx context.Context, c *entity.CometScraper) error
 	UpdateStatus(ctx context.Context, comet *entity.CometScraper) (err error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, tenantID, id string) error
 }
 
 type pgsqlCometScraperRepository struct {
@@ -29,8 +36,12 @@ func NewPgsqlCometScraperRepository(db *sql.DB) CometScraperRepository {
 }
 
 func (r *pgsqlCometScraperRepository) UpdateStatus(ctx context.Context, comet *entity.CometScraper) (err error) {
-	query := "UPDATE comet_scraper SET status = $1, updated_at = $2 WHERE uuid = $3"
-	res, err := r.db.ExecContext(ctx, query, comet.Status, comet.UpdatedAt, comet.Uuid)
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.update_status")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.update_status")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "UPDATE comet_scraper SET status = $1, updated_at = $2 WHERE uuid = $3 AND tenant_id = $4"
+	res, err := r.db.ExecContext(ctx, query, comet.Status, comet.UpdatedAt, comet.Uuid, comet.TenantID)
 	if err != nil {
 		return
 	}
@@ -48,40 +59,111 @@ func (r *pgsqlCometScraperRepository) UpdateStatus(ctx context.Context, comet *e
 }
 
 func (r *pgsqlCometScraperRepository) Update(ctx context.Context, comet *entity.CometScraper) (err error) {
-	query := `UPDATE comet_scraper SET status = $1, applicant = $2,time_taken = $3, updated_at = $4 WHERE uuid = $5`
-	res, err := r.db.ExecContext(ctx, query, comet.Status, comet.Applicant, comet.TimeTaken, comet.UpdatedAt, comet.Uuid)
-	if err != nil {
-		return
-	}
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.update")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.update")
+	defer func() { tracing.End(span, spanError(err)) }()
 
-	affect, err := res.RowsAffected()
-	if err != nil {
-		return
-	}
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `UPDATE comet_scraper SET status = $1, applicant = $2,time_taken = $3, attempts = $4, checkpoint = $5, updated_at = $6 WHERE uuid = $7 AND tenant_id = $8`
+		res, err := tx.ExecContext(ctx, query, comet.Status, comet.Applicant, comet.TimeTaken, comet.Attempts, comet.Checkpoint, comet.UpdatedAt, comet.Uuid, comet.TenantID)
+		if err != nil {
+			return err
+		}
 
-	if affect != 1 {
-		err = fmt.Errorf("weird behavior, total affected: %d", affect)
-	}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	return
+		if affect != 1 {
+			return fmt.Errorf("weird behavior, total affected: %d", affect)
+		}
+
+		return saveCandidate(ctx, tx, comet)
+	})
 }
 
 func (r *pgsqlCometScraperRepository) Create(ctx context.Context, cometScraper *entity.CometScraper) (err error) {
-	query := `INSERT INTO comet_scraper (uuid, time_taken, applicant, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = r.db.ExecContext(ctx, query, cometScraper.Uuid, cometScraper.TimeTaken, cometScraper.Applicant, cometScraper.Status, cometScraper.CreatedAt, cometScraper.UpdatedAt)
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.create")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.create")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `INSERT INTO comet_scraper (uuid, tenant_id, time_taken, applicant, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = r.db.ExecContext(ctx, query, cometScraper.Uuid, cometScraper.TenantID, cometScraper.TimeTaken, cometScraper.Applicant, cometScraper.Status, cometScraper.CreatedAt, cometScraper.UpdatedAt)
 	return
 }
 
-func (r *pgsqlCometScraperRepository) GetByID(ctx context.Context, id string) (cometScraper entity.CometScraper, err error) {
-	query := "SELECT uuid, applicant, time_taken, status, created_at, updated_at FROM comet_scraper WHERE uuid = $1"
-	err = r.db.QueryRowContext(ctx, query, id).Scan(&cometScraper.Uuid, &cometScraper.Applicant, &cometScraper.TimeTaken, &cometScraper.Status, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
+func (r *pgsqlCometScraperRepository) GetByID(ctx context.Context, tenantID, id string) (cometScraper entity.CometScraper, err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.get_by_id")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.get_by_id")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "SELECT uuid, tenant_id, applicant, time_taken, status, attempts, checkpoint, created_at, updated_at FROM comet_scraper WHERE uuid = $1 AND tenant_id = $2"
+	err = r.db.QueryRowContext(ctx, query, id, tenantID).Scan(&cometScraper.Uuid, &cometScraper.TenantID, &cometScraper.Applicant, &cometScraper.TimeTaken, &cometScraper.Status, &cometScraper.Attempts, &cometScraper.Checkpoint, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
 
 	return
 }
 
-func (r *pgsqlCometScraperRepository) Fetch(ctx context.Context) (cometScrapers []entity.CometScraper, err error) {
-	query := "SELECT uuid, time_taken, applicant, status, created_at, updated_at FROM comet_scraper"
-	rows, err := r.db.QueryContext(ctx, query)
+// sortColumns whitelists the columns a listing can be ordered by
+var sortColumns = map[string]string{
+	entity.SortByCreatedAt: "created_at",
+	entity.SortByUpdatedAt: "updated_at",
+}
+
+// filterConditions build the WHERE clause shared by Fetch and Count
+func filterConditions(tenantID string, filter entity.CometScraperFilter) (conditions []string, args []interface{}) {
+	args = append(args, tenantID)
+	conditions = append(conditions, fmt.Sprintf("tenant_id = $%d", len(args)))
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	return
+}
+
+func (r *pgsqlCometScraperRepository) Fetch(ctx context.Context, tenantID string, filter entity.CometScraperFilter) (cometScrapers []entity.CometScraper, err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.fetch")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.fetch")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	sortColumn, ok := sortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = sortColumns[entity.SortByCreatedAt]
+	}
+
+	direction, comparator := "DESC", "<"
+	if filter.SortOrder == entity.SortOrderAsc {
+		direction, comparator = "ASC", ">"
+	}
+
+	conditions, args := filterConditions(tenantID, filter)
+	if filter.After != nil {
+		args = append(args, filter.After.Value, filter.After.Uuid)
+		conditions = append(conditions, fmt.Sprintf("(%s, uuid) %s ($%d, $%d)", sortColumn, comparator, len(args)-1, len(args)))
+	}
+
+	query := fmt.Sprintf(
+		"SELECT uuid, tenant_id, time_taken, applicant, status, attempts, created_at, updated_at FROM comet_scraper WHERE %s ORDER BY %s %s, uuid %s",
+		strings.Join(conditions, " AND "), sortColumn, direction, direction,
+	)
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return cometScrapers, err
 	}
@@ -90,7 +172,7 @@ func (r *pgsqlCometScraperRepository) Fetch(ctx context.Context) (cometScrapers
 
 	for rows.Next() {
 		var cometScraper entity.CometScraper
-		err := rows.Scan(&cometScraper.Uuid, &cometScraper.TimeTaken, &cometScraper.Applicant, &cometScraper.Status, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
+		err := rows.Scan(&cometScraper.Uuid, &cometScraper.TenantID, &cometScraper.TimeTaken, &cometScraper.Applicant, &cometScraper.Status, &cometScraper.Attempts, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
 		if err != nil {
 			return cometScrapers, err
 		}
@@ -98,24 +180,140 @@ func (r *pgsqlCometScraperRepository) Fetch(ctx context.Context) (cometScrapers
 		cometScrapers = append(cometScrapers, cometScraper)
 	}
 
-	return cometScrapers, nil
+	return cometScrapers, rows.Err()
 }
 
-func (r *pgsqlCometScraperRepository) Delete(ctx context.Context, id string) (err error) {
-	query := "DELETE FROM comet_scraper WHERE uuid = $1"
-	res, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return
-	}
+func (r *pgsqlCometScraperRepository) Count(ctx context.Context, tenantID string, filter entity.CometScraperFilter) (total int, err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.count")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.count")
+	defer func() { tracing.End(span, spanError(err)) }()
 
-	affect, err := res.RowsAffected()
+	conditions, args := filterConditions(tenantID, filter)
+	query := "SELECT COUNT(*) FROM comet_scraper WHERE " + strings.Join(conditions, " AND ")
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&total)
+
+	return
+}
+
+// searchHighlight marks the matches in the same applicant fields search_vector indexes
+const searchHighlight = `ts_headline('simple', jsonb_build_object(
+	'role', applicant->'role',
+	'description', applicant->'description',
+	'skills', jsonb_path_query_array(applicant, '$.skill[*].name'),
+	'experience_titles', jsonb_path_query_array(applicant, '$.experience[*].title'),
+	'experience_descriptions', jsonb_path_query_array(applicant, '$.experience[*].desc')
+), q, 'StartSel=<mark>, StopSel=</mark>')`
+
+func (r *pgsqlCometScraperRepository) Search(ctx context.Context, tenantID, q string, limit int) (results []entity.CometScraperSearchResult, err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.search")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.search")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `SELECT uuid, tenant_id, time_taken, applicant, status, attempts, created_at, updated_at, ts_rank(search_vector, q) AS rank, ` + searchHighlight + `
+		FROM comet_scraper, websearch_to_tsquery('simple', $2) q
+		WHERE tenant_id = $1 AND search_vector @@ q
+		ORDER BY rank DESC, uuid
+		LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, tenantID, q, limit)
 	if err != nil {
-		return
+		return results, err
 	}
 
-	if affect != 1 {
-		err = fmt.Errorf("weird behavior, total affected: %d", affect)
+	defer rows.Close()
+
+	for rows.Next() {
+		var result entity.CometScraperSearchResult
+		err := rows.Scan(&result.Uuid, &result.TenantID, &result.TimeTaken, &result.Applicant, &result.Status, &result.Attempts, &result.CreatedAt, &result.UpdatedAt, &result.Rank, &result.Highlight)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
 	}
 
-	return
+	return results, rows.Err()
+}
+
+// streamBatchSize is how many rows each FETCH pulls from the server-side cursor
+const streamBatchSize = 500
+
+// Stream calls fn on every crawl updated after since, oldest first, without loading them all in memory
+func (r *pgsqlCometScraperRepository) Stream(ctx context.Context, tenantID string, since *time.Time, fn func(entity.CometScraper) error) (err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.stream")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.stream")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "DECLARE comet_scraper_stream NO SCROLL CURSOR FOR SELECT uuid, tenant_id, time_taken, applicant, status, attempts, created_at, updated_at FROM comet_scraper WHERE tenant_id = $1"
+		args := []interface{}{tenantID}
+		if since != nil {
+			args = append(args, *since)
+			query += " AND updated_at > $2"
+		}
+		query += " ORDER BY updated_at, uuid"
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH %d FROM comet_scraper_stream", streamBatchSize)
+		for {
+			rows, err := tx.QueryContext(ctx, fetch)
+			if err != nil {
+				return err
+			}
+
+			fetched := 0
+			for rows.Next() {
+				var cometScraper entity.CometScraper
+				err := rows.Scan(&cometScraper.Uuid, &cometScraper.TenantID, &cometScraper.TimeTaken, &cometScraper.Applicant, &cometScraper.Status, &cometScraper.Attempts, &cometScraper.CreatedAt, &cometScraper.UpdatedAt)
+				if err == nil {
+					err = fn(cometScraper)
+				}
+				if err != nil {
+					rows.Close()
+					return err
+				}
+				fetched++
+			}
+
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			if fetched < streamBatchSize {
+				return nil
+			}
+		}
+	})
+}
+
+func (r *pgsqlCometScraperRepository) Delete(ctx context.Context, tenantID, id string) (err error) {
+	defer metrics.ObserveDatastore("postgres", "comet_scraper.delete")()
+	ctx, span := tracing.Start(ctx, "postgres.comet_scraper.delete")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "DELETE FROM comet_scraper WHERE uuid = $1 AND tenant_id = $2"
+		res, err := tx.ExecContext(ctx, query, id, tenantID)
+		if err != nil {
+			return err
+		}
+
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affect != 1 {
+			return fmt.Errorf("weird behavior, total affected: %d", affect)
+		}
+
+		if err = deleteArtifacts(ctx, tx, tenantID, id); err != nil {
+			return err
+		}
+
+		return deleteCandidate(ctx, tx, tenantID, id)
+	})
 }
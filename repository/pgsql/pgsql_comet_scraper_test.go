@@ -0,0 +1,31 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/repository/pgsql"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRemovesArtifacts(t *testing.T) {
+	db := SetupDatabase(t)
+	ctx := context.Background()
+	tenantID := uuid.NewV4().String()
+	id := uuid.NewV4().String()
+
+	cometScraperRepo := pgsql.NewPgsqlCometScraperRepository(db)
+	require.NoError(t, cometScraperRepo.Create(ctx, &entity.CometScraper{Uuid: id, TenantID: tenantID, Status: entity.Fail, CreatedAt: time.Now(), UpdatedAt: time.Now()}))
+	artifactRepo := pgsql.NewPgsqlArtifactRepository(db)
+	require.NoError(t, artifactRepo.Create(ctx, &entity.Artifact{Uuid: id, TenantID: tenantID, Name: "login.png", Step: "login", ContentType: "image/png", CreatedAt: time.Now()}))
+
+	require.NoError(t, cometScraperRepo.Delete(ctx, tenantID, id))
+
+	artifacts, err := artifactRepo.FetchByScraper(ctx, tenantID, id)
+	require.NoError(t, err)
+	assert.Empty(t, artifacts)
+}
@@ -0,0 +1,155 @@
+package pgsql
+
+import (
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ScheduleRepository represent the re-crawl schedule's repository contract
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *entity.Schedule) error
+	GetByID(ctx context.Context, tenantID, id string) (entity.Schedule, error)
+	Fetch(ctx context.Context, tenantID string, limit int) ([]entity.Schedule, error)
+	Count(ctx context.Context, tenantID string) (int, error)
+	Delete(ctx context.Context, tenantID, id string) error
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]entity.Schedule, error)
+	Claim(ctx context.Context, schedule *entity.Schedule, nextRunAt time.Time) (bool, error)
+	SaveRun(ctx context.Context, id string, runAt time.Time, runError string) error
+}
+
+type pgsqlScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlScheduleRepository will create new a scheduleRepository object representation of ScheduleRepository interface
+func NewPgsqlScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &pgsqlScheduleRepository{
+		db: db,
+	}
+}
+
+const scheduleColumns = "uuid, tenant_id, scraper_uuid, cron, credentials, next_run_at, last_run_at, last_error, created_at"
+
+func scanSchedule(row interface{ Scan(...interface{}) error }) (schedule entity.Schedule, err error) {
+	err = row.Scan(&schedule.Uuid, &schedule.TenantID, &schedule.ScraperUuid, &schedule.Cron, &schedule.Credentials,
+		&schedule.NextRunAt, &schedule.LastRunAt, &schedule.LastError, &schedule.CreatedAt)
+	return
+}
+
+func (r *pgsqlScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) (err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.create")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.create")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `INSERT INTO comet_scraper_schedules (uuid, tenant_id, scraper_uuid, cron, credentials, next_run_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = r.db.ExecContext(ctx, query, schedule.Uuid, schedule.TenantID, schedule.ScraperUuid, schedule.Cron, schedule.Credentials, schedule.NextRunAt, schedule.CreatedAt)
+	return
+}
+
+func (r *pgsqlScheduleRepository) GetByID(ctx context.Context, tenantID, id string) (schedule entity.Schedule, err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.get_by_id")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.get_by_id")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "SELECT " + scheduleColumns + " FROM comet_scraper_schedules WHERE uuid = $1 AND tenant_id = $2"
+	return scanSchedule(r.db.QueryRowContext(ctx, query, id, tenantID))
+}
+
+func (r *pgsqlScheduleRepository) Fetch(ctx context.Context, tenantID string, limit int) (schedules []entity.Schedule, err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.fetch")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.fetch")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "SELECT " + scheduleColumns + " FROM comet_scraper_schedules WHERE tenant_id = $1 ORDER BY created_at, uuid LIMIT $2"
+	return r.query(ctx, query, tenantID, limit)
+}
+
+func (r *pgsqlScheduleRepository) Count(ctx context.Context, tenantID string) (total int, err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.count")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.count")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM comet_scraper_schedules WHERE tenant_id = $1", tenantID).Scan(&total)
+	return
+}
+
+func (r *pgsqlScheduleRepository) Delete(ctx context.Context, tenantID, id string) (err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.delete")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.delete")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM comet_scraper_schedules WHERE uuid = $1 AND tenant_id = $2", id, tenantID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		err = sql.ErrNoRows
+	}
+	return
+}
+
+// FetchDue returns the schedules of every tenant whose next run is at or before now, the most late first
+func (r *pgsqlScheduleRepository) FetchDue(ctx context.Context, now time.Time, limit int) (schedules []entity.Schedule, err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.fetch_due")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.fetch_due")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "SELECT " + scheduleColumns + " FROM comet_scraper_schedules WHERE next_run_at <= $1 ORDER BY next_run_at LIMIT $2"
+	return r.query(ctx, query, now, limit)
+}
+
+// Claim moves a due schedule to its next run, false when another instance claimed that run first
+func (r *pgsqlScheduleRepository) Claim(ctx context.Context, schedule *entity.Schedule, nextRunAt time.Time) (claimed bool, err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.claim")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.claim")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := "UPDATE comet_scraper_schedules SET next_run_at = $1 WHERE uuid = $2 AND next_run_at = $3"
+	res, err := r.db.ExecContext(ctx, query, nextRunAt, schedule.Uuid, schedule.NextRunAt)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	return affect == 1, err
+}
+
+// SaveRun records when a schedule last ran and why it failed, runError being empty when it didn't
+func (r *pgsqlScheduleRepository) SaveRun(ctx context.Context, id string, runAt time.Time, runError string) (err error) {
+	defer metrics.ObserveDatastore("postgres", "schedule.save_run")()
+	ctx, span := tracing.Start(ctx, "postgres.schedule.save_run")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	_, err = r.db.ExecContext(ctx, "UPDATE comet_scraper_schedules SET last_run_at = $1, last_error = $2 WHERE uuid = $3", runAt, runError, id)
+	return
+}
+
+func (r *pgsqlScheduleRepository) query(ctx context.Context, query string, args ...interface{}) (schedules []entity.Schedule, err error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	schedules = []entity.Schedule{}
+	for rows.Next() {
+		var schedule entity.Schedule
+		if schedule, err = scanSchedule(rows); err != nil {
+			return
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	err = rows.Err()
+	return
+}
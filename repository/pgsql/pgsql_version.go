@@ -0,0 +1,74 @@
+package pgsql
+
+import (
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"context"
+	"database/sql"
+)
+
+// VersionRepository represent the crawl history's repository contract
+type VersionRepository interface {
+	Create(ctx context.Context, version *entity.CometScraperVersion) error
+	FetchByPerson(ctx context.Context, tenantID, personKey string) ([]entity.CometScraperVersion, error)
+}
+
+type pgsqlVersionRepository struct {
+	db *sql.DB
+}
+
+// NewPgsqlVersionRepository will create new a versionRepository object representation of VersionRepository interface
+func NewPgsqlVersionRepository(db *sql.DB) VersionRepository {
+	return &pgsqlVersionRepository{
+		db: db,
+	}
+}
+
+// Create archives a candidate as the next version of its person, setting version.Version.
+// The person's versions are locked until the transaction ends, so concurrent crawls of a person get their own number.
+func (r *pgsqlVersionRepository) Create(ctx context.Context, version *entity.CometScraperVersion) (err error) {
+	defer metrics.ObserveDatastore("postgres", "version.create")()
+	ctx, span := tracing.Start(ctx, "postgres.version.create")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1::varchar || '/' || $2::varchar))", version.TenantID, version.PersonKey)
+		if err != nil {
+			return err
+		}
+
+		query := `INSERT INTO comet_scraper_versions (uuid, tenant_id, person_key, version, applicant, time_taken, crawled_at, created_at)
+			SELECT $1::varchar, $2::varchar, $3::varchar, COALESCE(MAX(version), 0) + 1, $4::jsonb, $5::varchar, $6::timestamp, $7::timestamp
+			FROM comet_scraper_versions WHERE tenant_id = $2 AND person_key = $3
+			RETURNING version`
+		return tx.QueryRowContext(ctx, query, version.Uuid, version.TenantID, version.PersonKey, version.Applicant, version.TimeTaken, version.CrawledAt, version.CreatedAt).Scan(&version.Version)
+	})
+}
+
+func (r *pgsqlVersionRepository) FetchByPerson(ctx context.Context, tenantID, personKey string) (versions []entity.CometScraperVersion, err error) {
+	defer metrics.ObserveDatastore("postgres", "version.fetch_by_person")()
+	ctx, span := tracing.Start(ctx, "postgres.version.fetch_by_person")
+	defer func() { tracing.End(span, spanError(err)) }()
+
+	query := `SELECT uuid, tenant_id, person_key, version, applicant, time_taken, crawled_at, created_at FROM comet_scraper_versions
+		WHERE person_key = $1 AND tenant_id = $2 ORDER BY version DESC`
+	rows, err := r.db.QueryContext(ctx, query, personKey, tenantID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	versions = []entity.CometScraperVersion{}
+	for rows.Next() {
+		var version entity.CometScraperVersion
+		err = rows.Scan(&version.Uuid, &version.TenantID, &version.PersonKey, &version.Version, &version.Applicant, &version.TimeTaken, &version.CrawledAt, &version.CreatedAt)
+		if err != nil {
+			return
+		}
+		versions = append(versions, version)
+	}
+
+	err = rows.Err()
+	return
+}
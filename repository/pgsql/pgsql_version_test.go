@@ -0,0 +1,83 @@
+package pgsql_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/infrastructure/datastore"
+	"cometScraper/repository/pgsql"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SetupDatabase connects to the migrated database of TEST_DATABASE_URL, skipping the test without one
+func SetupDatabase(t *testing.T) *sql.DB {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := datastore.NewDatabase(databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := datastore.NewMigrator(context.Background(), db)
+	require.NoError(t, err)
+	defer migrator.Close()
+	require.NoError(t, migrator.Up())
+
+	return db
+}
+
+func TestVersionCreateConcurrently(t *testing.T) {
+	db := SetupDatabase(t)
+	ctx := context.Background()
+	tenantID := uuid.NewV4().String()
+	personKey := uuid.NewV4().String()
+
+	const crawls = 8
+	cometScraperRepo := pgsql.NewPgsqlCometScraperRepository(db)
+	ids := make([]string, crawls)
+	for i := range ids {
+		ids[i] = uuid.NewV4().String()
+		require.NoError(t, cometScraperRepo.Create(ctx, &entity.CometScraper{Uuid: ids[i], TenantID: tenantID, Status: entity.Success, CreatedAt: time.Now(), UpdatedAt: time.Now()}))
+	}
+	t.Cleanup(func() { _, _ = db.Exec("DELETE FROM comet_scraper WHERE tenant_id = $1", tenantID) })
+
+	versionRepo := pgsql.NewPgsqlVersionRepository(db)
+	var wg sync.WaitGroup
+	errs := make([]error, crawls)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = versionRepo.Create(ctx, &entity.CometScraperVersion{
+				Uuid:      ids[i],
+				TenantID:  tenantID,
+				PersonKey: personKey,
+				CrawledAt: time.Now(),
+				CreatedAt: time.Now(),
+			})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	versions, err := versionRepo.FetchByPerson(ctx, tenantID, personKey)
+	require.NoError(t, err)
+	numbers := make([]int, len(versions))
+	for i, version := range versions {
+		numbers[i] = version.Version
+	}
+	sort.Ints(numbers)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, numbers)
+}
@@ -1,16 +1,19 @@
 package redis
 
 import (
+	"context"
 	"time"
 
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
 	"github.com/go-redis/redis"
 )
 
 // RedisRepository represent the redis repositories
 type RedisRepository interface {
-	Set(key string, value interface{}, exp time.Duration) error
-	Get(key string) (string, error)
-	Delete(key string) error
+	Set(ctx context.Context, key string, value interface{}, exp time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
 }
 
 type redisRepository struct {
@@ -25,16 +28,35 @@ func NewRedisRepository(client *redis.Client) RedisRepository {
 }
 
 // Set attaches the redis repository and set the data
-func (r *redisRepository) Set(key string, value interface{}, exp time.Duration) error {
-	return r.client.Set(key, value, exp).Err()
+func (r *redisRepository) Set(ctx context.Context, key string, value interface{}, exp time.Duration) (err error) {
+	defer metrics.ObserveDatastore("redis", "set")()
+	ctx, span := tracing.Start(ctx, "redis.set")
+	defer func() { tracing.End(span, err) }()
+
+	return r.client.WithContext(ctx).Set(key, value, exp).Err()
 }
 
 // Get attaches the redis repository and get the data
-func (r *redisRepository) Get(key string) (string, error) {
-	return r.client.Get(key).Result()
+func (r *redisRepository) Get(ctx context.Context, key string) (value string, err error) {
+	defer metrics.ObserveDatastore("redis", "get")()
+	ctx, span := tracing.Start(ctx, "redis.get")
+	defer func() {
+		// a missing key is an expected outcome, not a failed call
+		if err == redis.Nil {
+			tracing.End(span, nil)
+			return
+		}
+		tracing.End(span, err)
+	}()
+
+	return r.client.WithContext(ctx).Get(key).Result()
 }
 
 // Delete delete the data
-func (r *redisRepository) Delete(key string) error {
-	return r.client.Del(key).Err()
+func (r *redisRepository) Delete(ctx context.Context, key string) (err error) {
+	defer metrics.ObserveDatastore("redis", "delete")()
+	ctx, span := tracing.Start(ctx, "redis.delete")
+	defer func() { tracing.End(span, err) }()
+
+	return r.client.WithContext(ctx).Del(key).Err()
 }
@@ -1,6 +1,7 @@
 package redis_test
 
 import (
+	"context"
 	"log"
 	"testing"
 	"time"
@@ -27,7 +28,7 @@ func SetupRedis() redisRepo.RedisRepository {
 
 func TestSet(t *testing.T) {
 	redisRepository := SetupRedis()
-	err := redisRepository.Set("ping", "pong", time.Duration(0))
+	err := redisRepository.Set(context.Background(), "ping", "pong", time.Duration(0))
 	assert.NoError(t, err)
 }
 
@@ -35,14 +36,14 @@ func TestGet(t *testing.T) {
 	redisRepository := SetupRedis()
 	key, val, exp := "ping", "pong", time.Duration(0)
 
-	value, err := redisRepository.Get(key)
+	value, err := redisRepository.Get(context.Background(), key)
 	assert.NotNil(t, err)
 	assert.Equal(t, value, "")
 
-	err = redisRepository.Set(key, val, exp)
+	err = redisRepository.Set(context.Background(), key, val, exp)
 	assert.NoError(t, err)
 
-	value, err = redisRepository.Get(key)
+	value, err = redisRepository.Get(context.Background(), key)
 	assert.NoError(t, err)
 	assert.Equal(t, value, val)
 }
@@ -2,10 +2,12 @@ package applicant
 
 import (
 	"cometScraper/tools/scraper/pkg/element"
+	"cometScraper/tools/scraper/pkg/parser"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 )
 
 func clearString(s string) string {
@@ -16,16 +18,35 @@ func clearString(s string) string {
 }
 
 type Skill struct {
-	Name string `json:"name"`
-	Time string `json:"time"`
+	Name   string `json:"name"`
+	Time   string `json:"time"`
+	Months int    `json:"months"`
 }
 
 type Job struct {
-	Title       string `json:"title"`
-	Skill       string `json:"skill"`
-	Desc        string `json:"desc"`
-	Period      string `json:"period"`
-	PeriodCount string `json:"period_count"`
+	Title       string     `json:"title"`
+	Skill       string     `json:"skill"`
+	Desc        string     `json:"desc"`
+	Period      string     `json:"period"`
+	PeriodCount string     `json:"period_count"`
+	Start       *time.Time `json:"start"`
+	End         *time.Time `json:"end"`
+	Months      int        `json:"months"`
+}
+
+// parse fill the typed fields of the job from its scraped period, preferring the
+// count shown on the page over the one computed from the dates
+func (j *Job) parse(now time.Time) {
+	period, ok := parser.ParsePeriod(j.Period)
+	if ok {
+		j.Start = &period.Start
+		j.End = period.End
+		j.Months = period.Months(now)
+	}
+
+	if months, ok := parser.ParseDuration(j.PeriodCount); ok {
+		j.Months = months
+	}
 }
 
 type Applicant interface {
@@ -49,13 +70,14 @@ type Applicant interface {
 }
 
 type Candidate struct {
-	ImageUrl         string  `json:"image_url"`
-	Name             string  `json:"name"`
-	Role             string  `json:"role"`
-	Experience       []Job   `json:"experience"`
-	Description      string  `json:"description"`
-	Skill            []Skill `json:"skill"`
-	TimeOfExperience string  `json:"time_of_experience"`
+	ImageUrl          string  `json:"image_url"`
+	Name              string  `json:"name"`
+	Role              string  `json:"role"`
+	Experience        []Job   `json:"experience"`
+	Description       string  `json:"description"`
+	Skill             []Skill `json:"skill"`
+	TimeOfExperience  string  `json:"time_of_experience"`
+	YearsOfExperience float64 `json:"years_of_experience"`
 }
 
 // Value Make the Candidate struct implement the driver.Valuer interface. This method simply returns the JSON-encoded representation of the struct.
@@ -134,12 +156,22 @@ func (c *Candidate) GetSkillTime(key int) *string {
 }
 
 func (c *Candidate) Clear() {
+	now := time.Now()
 	for key, value := range c.Skill {
 		c.Skill[key].Time = clearString(value.Time)
+		c.Skill[key].Months, _ = parser.ParseDuration(c.Skill[key].Time)
 	}
 	for key, value := range c.Experience {
 		c.Experience[key].Desc = clearString(value.Desc)
+		c.Experience[key].parse(now)
 	}
+	c.ParseBaseInfo()
+}
+
+// ParseBaseInfo fill the typed fields of the base info, as soon as it is scraped since profiles
+// without skills nor experiences are never cleared
+func (c *Candidate) ParseBaseInfo() {
+	c.YearsOfExperience, _ = parser.ParseYears(c.TimeOfExperience)
 }
 
 func (c *Candidate) GenerateExperienceElementsAndValue(e element.ExperienceElements) []element.ElementAndValue {
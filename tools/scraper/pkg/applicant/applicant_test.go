@@ -0,0 +1,19 @@
+package applicant_test
+
+import (
+	"testing"
+
+	"cometScraper/tools/scraper/pkg/applicant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBaseInfo(t *testing.T) {
+	// a profile without skills nor experiences is never cleared, only its base info is parsed
+	candidate := applicant.Candidate{TimeOfExperience: "7 ans"}
+	candidate.ParseBaseInfo()
+	assert.Equal(t, float64(7), candidate.YearsOfExperience)
+
+	candidate = applicant.Candidate{TimeOfExperience: "18 mois"}
+	candidate.Clear()
+	assert.Equal(t, 1.5, candidate.YearsOfExperience)
+}
@@ -0,0 +1,129 @@
+package applicant
+
+import "strings"
+
+// Change is a value that differs between two versions of a candidate
+type Change struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SkillChange is a skill found in both versions, used for a different time
+type SkillChange struct {
+	Name string `json:"name"`
+	Time Change `json:"time"`
+}
+
+// ExperienceChange is an experience found in both versions, by title and start, with the fields that changed
+type ExperienceChange struct {
+	Title  string            `json:"title"`
+	Period string            `json:"period"`
+	Fields map[string]Change `json:"fields"`
+}
+
+// Diff is what changed in a candidate from one version to another
+type Diff struct {
+	Role               *Change            `json:"role"`
+	AddedSkills        []Skill            `json:"added_skills"`
+	RemovedSkills      []Skill            `json:"removed_skills"`
+	ChangedSkills      []SkillChange      `json:"changed_skills"`
+	AddedExperiences   []Job              `json:"added_experiences"`
+	RemovedExperiences []Job              `json:"removed_experiences"`
+	ChangedExperiences []ExperienceChange `json:"changed_experiences"`
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// jobKey identifies an experience across crawls, its end and counts change while it goes on
+func jobKey(job Job) string {
+	start := ""
+	if job.Start != nil {
+		start = job.Start.Format("2006-01")
+	} else if from, _, ok := strings.Cut(job.Period, "-"); ok {
+		start = normalize(from)
+	}
+	return normalize(job.Title) + "|" + start
+}
+
+// NewDiff compares two versions of a candidate. Skills are matched by name and experiences by title and start,
+// the months and period counts of ongoing experiences growing with time are left out.
+func NewDiff(from, to Candidate) Diff {
+	diff := Diff{
+		AddedSkills:        []Skill{},
+		RemovedSkills:      []Skill{},
+		ChangedSkills:      []SkillChange{},
+		AddedExperiences:   []Job{},
+		RemovedExperiences: []Job{},
+		ChangedExperiences: []ExperienceChange{},
+	}
+
+	if normalize(from.Role) != normalize(to.Role) {
+		diff.Role = &Change{From: from.Role, To: to.Role}
+	}
+
+	fromSkills := map[string][]Skill{}
+	for _, skill := range from.Skill {
+		key := normalize(skill.Name)
+		fromSkills[key] = append(fromSkills[key], skill)
+	}
+	for _, skill := range to.Skill {
+		key := normalize(skill.Name)
+		previous, ok := fromSkills[key]
+		if !ok || len(previous) == 0 {
+			diff.AddedSkills = append(diff.AddedSkills, skill)
+			continue
+		}
+
+		fromSkills[key] = previous[1:]
+		if normalize(previous[0].Time) != normalize(skill.Time) {
+			diff.ChangedSkills = append(diff.ChangedSkills, SkillChange{Name: skill.Name, Time: Change{From: previous[0].Time, To: skill.Time}})
+		}
+	}
+	for _, skill := range from.Skill {
+		key := normalize(skill.Name)
+		if len(fromSkills[key]) > 0 {
+			diff.RemovedSkills = append(diff.RemovedSkills, fromSkills[key][0])
+			fromSkills[key] = fromSkills[key][1:]
+		}
+	}
+
+	fromJobs := map[string][]Job{}
+	for _, job := range from.Experience {
+		key := jobKey(job)
+		fromJobs[key] = append(fromJobs[key], job)
+	}
+	for _, job := range to.Experience {
+		key := jobKey(job)
+		previous, ok := fromJobs[key]
+		if !ok || len(previous) == 0 {
+			diff.AddedExperiences = append(diff.AddedExperiences, job)
+			continue
+		}
+
+		fromJobs[key] = previous[1:]
+		fields := map[string]Change{}
+		for name, values := range map[string][2]string{
+			"skill":  {previous[0].Skill, job.Skill},
+			"desc":   {previous[0].Desc, job.Desc},
+			"period": {previous[0].Period, job.Period},
+		} {
+			if normalize(values[0]) != normalize(values[1]) {
+				fields[name] = Change{From: values[0], To: values[1]}
+			}
+		}
+		if len(fields) > 0 {
+			diff.ChangedExperiences = append(diff.ChangedExperiences, ExperienceChange{Title: job.Title, Period: job.Period, Fields: fields})
+		}
+	}
+	for _, job := range from.Experience {
+		key := jobKey(job)
+		if len(fromJobs[key]) > 0 {
+			diff.RemovedExperiences = append(diff.RemovedExperiences, fromJobs[key][0])
+			fromJobs[key] = fromJobs[key][1:]
+		}
+	}
+
+	return diff
+}
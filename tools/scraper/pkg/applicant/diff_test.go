@@ -0,0 +1,76 @@
+package applicant_test
+
+import (
+	"testing"
+	"time"
+
+	"cometScraper/tools/scraper/pkg/applicant"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(year int, m time.Month) *time.Time {
+	t := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestNewDiff(t *testing.T) {
+	from := applicant.Candidate{
+		Role: "Développeur Go",
+		Skill: []applicant.Skill{
+			{Name: "Go", Time: "3 ans"},
+			{Name: "PHP", Time: "2 ans"},
+			{Name: "Docker", Time: "1 an"},
+		},
+		Experience: []applicant.Job{
+			{Title: "Backend", Skill: "Go", Period: "janv. 2020 - Aujourd'hui", PeriodCount: "2 ans", Start: month(2020, time.January)},
+			{Title: "Stage", Period: "juin 2018 - déc. 2018", Start: month(2018, time.June), End: month(2018, time.December)},
+		},
+	}
+	to := applicant.Candidate{
+		Role: "Lead Développeur Go",
+		Skill: []applicant.Skill{
+			{Name: "go", Time: "4 ans"},
+			{Name: "Docker", Time: "1 an"},
+			{Name: "Kubernetes", Time: "6 mois"},
+		},
+		Experience: []applicant.Job{
+			{Title: "Lead", Period: "mars 2023 - Aujourd'hui", Start: month(2023, time.March)},
+			// ended, the count growing with time isn't a change
+			{Title: "backend", Skill: "Go", Period: "janv. 2020 - févr. 2023", PeriodCount: "3 ans", Start: month(2020, time.January), End: month(2023, time.February)},
+		},
+	}
+
+	diff := applicant.NewDiff(from, to)
+
+	assert.Equal(t, &applicant.Change{From: "Développeur Go", To: "Lead Développeur Go"}, diff.Role)
+	assert.Equal(t, []applicant.Skill{{Name: "Kubernetes", Time: "6 mois"}}, diff.AddedSkills)
+	assert.Equal(t, []applicant.Skill{{Name: "PHP", Time: "2 ans"}}, diff.RemovedSkills)
+	assert.Equal(t, []applicant.SkillChange{{Name: "go", Time: applicant.Change{From: "3 ans", To: "4 ans"}}}, diff.ChangedSkills)
+	assert.Equal(t, []applicant.Job{to.Experience[0]}, diff.AddedExperiences)
+	assert.Equal(t, []applicant.Job{from.Experience[1]}, diff.RemovedExperiences)
+	assert.Equal(t, []applicant.ExperienceChange{{
+		Title:  "backend",
+		Period: "janv. 2020 - févr. 2023",
+		Fields: map[string]applicant.Change{
+			"period": {From: "janv. 2020 - Aujourd'hui", To: "janv. 2020 - févr. 2023"},
+		},
+	}}, diff.ChangedExperiences)
+}
+
+func TestNewDiffSameCandidate(t *testing.T) {
+	candidate := applicant.Candidate{
+		Role:       "Développeur Go",
+		Skill:      []applicant.Skill{{Name: "Go", Time: "3 ans"}},
+		Experience: []applicant.Job{{Title: "Backend", Period: "janv. 2020 - Aujourd'hui"}},
+	}
+
+	diff := applicant.NewDiff(candidate, candidate)
+
+	assert.Nil(t, diff.Role)
+	assert.Empty(t, diff.AddedSkills)
+	assert.Empty(t, diff.RemovedSkills)
+	assert.Empty(t, diff.ChangedSkills)
+	assert.Empty(t, diff.AddedExperiences)
+	assert.Empty(t, diff.RemovedExperiences)
+	assert.Empty(t, diff.ChangedExperiences)
+}
@@ -0,0 +1,31 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// browserExecutables are the names chromedp's default allocator starts Chrome with
+var browserExecutables = []string{
+	"headless_shell",
+	"headless-shell",
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"google-chrome-beta",
+	"google-chrome-unstable",
+	"/usr/bin/google-chrome",
+}
+
+// CheckBrowser reports whether the Chrome the crawls are run in can be found
+func CheckBrowser(context.Context) error {
+	for _, name := range browserExecutables {
+		if _, err := exec.LookPath(name); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("no Chrome executable found")
+}
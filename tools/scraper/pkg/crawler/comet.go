@@ -2,20 +2,44 @@ package crawler
 
 import (
 	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
 	"cometScraper/tools/scraper/pkg/applicant"
 	"cometScraper/tools/scraper/pkg/element"
+	"cometScraper/utils/logger"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
-	"log"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// fetchImageScript downloads an image with the page's cookies and resolves to its data URL
+const fetchImageScript = `(async (src) => {
+	const response = await fetch(src, {credentials: 'include'});
+	if (!response.ok) {
+		throw new Error('image responded ' + response.status);
+	}
+	const blob = await response.blob();
+	return await new Promise((resolve, reject) => {
+		const reader = new FileReader();
+		reader.onload = () => resolve(reader.result);
+		reader.onerror = () => reject(reader.error);
+		reader.readAsDataURL(blob);
+	});
+})(%s)`
+
 func generateActions(child int, elementAndValue []element.ElementAndValue) []chromedp.Action {
 	childString := strconv.Itoa(child)
 	var actions []chromedp.Action
@@ -51,6 +75,40 @@ func rangeAndGetActions(rangeSize int, eAndVal []element.ElementAndValue) []chro
 	return actions
 }
 
+func GetActionsImage(imageUrl string, dataUrl *string) []chromedp.Action {
+	src, _ := json.Marshal(imageUrl)
+	return []chromedp.Action{
+		chromedp.Evaluate(fmt.Sprintf(fetchImageScript, src), dataUrl, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}),
+	}
+}
+
+// parseDataUrl split a base64 data URL into its content type and bytes
+func parseDataUrl(dataUrl string) (*Image, error) {
+	meta, data, found := strings.Cut(strings.TrimPrefix(dataUrl, "data:"), ",")
+	if !found || !strings.HasSuffix(meta, ";base64") {
+		return nil, errors.New("unexpected image data url")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		Data:        decoded,
+		ContentType: strings.TrimSuffix(meta, ";base64"),
+	}, nil
+}
+
+func GetActionsArtifacts(screenshot *[]byte, html *string) []chromedp.Action {
+	return []chromedp.Action{
+		chromedp.FullScreenshot(screenshot, 100),
+		chromedp.OuterHTML("html", html, chromedp.ByQuery),
+	}
+}
+
 func GetActionsLogin(elements element.Elements, credentials Credentials, currentUrl *string) []chromedp.Action {
 	return []chromedp.Action{
 		chromedp.Navigate(elements.GetUrls().StartPage),
@@ -94,140 +152,354 @@ func GetActionsToGetSkillAndExp(lenSkills, lenExperiences int, eAndValSkills, eA
 type Credentials struct {
 	Email string
 	Pass  string
+	// Codes delivers the verification codes the user submits when the login is challenged
+	Codes <-chan string
+	// ResumeUrl is a public resume link, crawled without logging in when set
+	ResumeUrl string
+	// Cookies of a logged in session the account's owner handed over, set instead of logging in when there are some
+	Cookies []Cookie
 }
 
 type cometScraper struct {
-	elements  element.Elements
-	applicant applicant.Applicant
+	elements   element.Elements
+	sessions   SessionStore
+	logger     logger.Logger
+	stepPolicy RetryPolicy
+	jobPolicy  RetryPolicy
 }
 
 type CometScraper interface {
-	StartCrawling(id string, credentials Credentials, cr chan Response, done chan struct{})
+	StartCrawling(ctx context.Context, id string, credentials Credentials, checkpoint Checkpoint, cr chan Response, done chan struct{})
 	GetUuid() string
 }
 
-func NewCometCrawler(elements element.Elements, applicant applicant.Applicant) CometScraper {
+// NewCometCrawler will create a CometScraper, stepPolicy retries each step of a crawl and jobPolicy the whole crawl.
+// Logins are reused through sessions, a nil store logs in on every crawl.
+func NewCometCrawler(elements element.Elements, sessions SessionStore, logger logger.Logger, stepPolicy, jobPolicy RetryPolicy) CometScraper {
+	if stepPolicy.Logger == nil {
+		stepPolicy.Logger = logger
+	}
+
 	return &cometScraper{
-		elements:  elements,
-		applicant: applicant,
+		elements:   elements,
+		sessions:   sessions,
+		logger:     logger,
+		stepPolicy: stepPolicy,
+		jobPolicy:  jobPolicy,
 	}
 }
 
 func (c *cometScraper) login(ctx context.Context, credentials Credentials) error {
+	if len(credentials.Cookies) > 0 {
+		return c.useSession(ctx, credentials.Cookies)
+	}
+
+	if c.restoreSession(ctx, credentials) {
+		return nil
+	}
+
 	var currentUrl string
 
-	err := chromedp.Run(ctx, GetActionsLogin(c.elements, credentials, &currentUrl)...)
+	err := c.run(ctx, "login", GetActionsLogin(c.elements, credentials, &currentUrl)...)
 	if err != nil {
 		return err
 	}
 
 	if currentUrl != c.elements.GetUrls().FreelancerDashboard {
+		challenged, err := c.challenged(ctx)
+		if err != nil {
+			return err
+		}
+		if challenged {
+			return ErrVerificationRequired
+		}
 		return errors.New(entity.FailedCredentials)
 	}
 
+	if err = c.saveSession(ctx, credentials); err != nil {
+		// the crawl goes on, the next one will just log in again
+		c.log(ctx).Warnw("could not save the session", "error", err)
+	}
+
 	return nil
 }
 
-func (c *cometScraper) getBaseInfo(ctx context.Context) (int, int, string, error) {
-	var resumeUrl string
+// getBaseInfo reads the profile from the resume, found from the logged in account's profile when resumeUrl is empty
+func (c *cometScraper) getBaseInfo(ctx context.Context, ap applicant.Applicant, resumeUrl string) (int, int, string, error) {
 	var nodesSkill, nodesExperience []*cdp.Node
 	var ok bool
-	err := chromedp.Run(ctx, GetActionsResume(c.elements, &resumeUrl, &ok)...)
-	if err != nil {
-		log.Println(err)
-		return 0, 0, "", err
+	if resumeUrl == "" {
+		err := c.run(ctx, "resume", GetActionsResume(c.elements, &resumeUrl, &ok)...)
+		if err != nil {
+			c.log(ctx).Warnw("could not find the resume", "error", err)
+			return 0, 0, "", err
+		}
 	}
 
-	err = chromedp.Run(ctx, GetActionsBaseInfo(resumeUrl, c.elements, c.applicant.Get(), &ok, &nodesSkill, &nodesExperience)...)
+	err := c.run(ctx, "base_info", GetActionsBaseInfo(resumeUrl, c.elements, ap.Get(), &ok, &nodesSkill, &nodesExperience)...)
 	if err != nil {
-		log.Println(err)
+		c.log(ctx).Warnw("could not read the base info", "error", err)
 		return 0, 0, "", err
 	}
+	ap.Get().ParseBaseInfo()
 
 	lenSkills := len(nodesSkill)
 	lenExperiences := len(nodesExperience)
 	return lenSkills, lenExperiences, resumeUrl, nil
 }
 
-func (c *cometScraper) getSkillsAndExp(ctx context.Context, lenSkills, lenExperiences int, resumeUrl string) error {
-	c.applicant.InitializeSkillAndExperience(lenSkills, lenExperiences)
-	eAndValExperience := c.applicant.GenerateExperienceElementsAndValue(c.elements.GetExperienceElements())
-	eAndValSkills := c.applicant.GenerateSkillsElementsAndValue(c.elements.GetSkillsElements())
-	err := chromedp.Run(ctx, GetActionsToGetSkillAndExp(lenSkills, lenExperiences, eAndValSkills, eAndValExperience, resumeUrl))
+func (c *cometScraper) getImage(ctx context.Context, ap applicant.Applicant) (*Image, error) {
+	var dataUrl string
+	err := c.run(ctx, "image", GetActionsImage(ap.Get().ImageUrl, &dataUrl)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDataUrl(dataUrl)
+}
+
+// captureArtifacts takes a full-page screenshot and a DOM snapshot of the current page, keeping whatever succeeded
+func (c *cometScraper) captureArtifacts(ctx context.Context, step string) []Artifact {
+	var screenshot []byte
+	var html string
+	if err := c.run(ctx, "artifacts", GetActionsArtifacts(&screenshot, &html)...); err != nil {
+		c.log(ctx).Warnw("could not capture the artifacts", "error", err)
+	}
+
+	var artifacts []Artifact
+	if len(screenshot) > 0 {
+		artifacts = append(artifacts, Artifact{Name: step + ".png", Step: step, Data: screenshot, ContentType: "image/png"})
+	}
+	if html != "" {
+		artifacts = append(artifacts, Artifact{Name: step + ".html", Step: step, Data: []byte(html), ContentType: "text/html; charset=utf-8"})
+	}
+
+	return artifacts
+}
+
+func (c *cometScraper) getSkillsAndExp(ctx context.Context, ap applicant.Applicant, lenSkills, lenExperiences int, resumeUrl string) error {
+	ap.InitializeSkillAndExperience(lenSkills, lenExperiences)
+	eAndValExperience := ap.GenerateExperienceElementsAndValue(c.elements.GetExperienceElements())
+	eAndValSkills := ap.GenerateSkillsElementsAndValue(c.elements.GetSkillsElements())
+	err := c.run(ctx, "skills_and_experiences", GetActionsToGetSkillAndExp(lenSkills, lenExperiences, eAndValSkills, eAndValExperience, resumeUrl))
 	if err != nil {
-		log.Println(err)
+		c.log(ctx).Warnw("could not read the skills and experiences", "error", err)
 		return err
 	}
-	c.applicant.Clear()
+	ap.Clear()
 	return nil
 }
 
+// log returns the logger of the crawl running in ctx
+func (c *cometScraper) log(ctx context.Context) logger.Logger {
+	return logger.WithContext(ctx, c.logger)
+}
+
+// run runs the actions of a step of the crawl in its own span
+func (c *cometScraper) run(ctx context.Context, step string, actions ...chromedp.Action) (err error) {
+	ctx, span := tracing.Start(ctx, "crawler."+step)
+	defer func() { tracing.End(span, err) }()
+
+	return chromedp.Run(ctx, actions...)
+}
+
 func (c *cometScraper) GetUuid() string {
 	return uuid.NewV4().String()
 }
 
-func (c *cometScraper) StartCrawling(id string, credentials Credentials, cr chan Response, done chan struct{}) {
+// StartCrawling runs a crawl from scratch, or from the stage after the checkpoint's when it has one.
+// Each crawl fills its own candidate, so crawls can run concurrently. The browser is closed when ctx is done.
+func (c *cometScraper) StartCrawling(ctx context.Context, id string, credentials Credentials, checkpoint Checkpoint, cr chan Response, done chan struct{}) {
+	defer close(done)
+
+	ctx = context.WithValue(ctx, entity.ProcessUuidKey, id)
+	ctx, span := tracing.Start(ctx, "crawler.crawl", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer span.End()
+
+	ap := applicant.NewApplicant()
+	if checkpoint.Stage != "" {
+		*ap.Get() = checkpoint.Applicant
+	}
+
+	start := time.Now()
+	response := Response{
+		Uuid:       id,
+		Status:     entity.Start,
+		Applicant:  *ap.Get(),
+		Checkpoint: checkpoint.Checkpoint,
+	}
+
+	for attempt := checkpoint.Attempts + 1; ; attempt++ {
+		response.Attempts = attempt
+		err := c.attempt(ctx, credentials, ap, &response, start, cr)
+		if err == nil {
+			return
+		}
+
+		if attempt-checkpoint.Attempts >= c.jobPolicy.MaxAttempts || !c.jobPolicy.retryable(err) {
+			span.SetStatus(codes.Error, err.Error())
+			_ = send(ctx, cr, response)
+			return
+		}
+
+		c.log(ctx).Warnw("crawl attempt failed, retrying", "attempt", attempt, "error", err)
+		response.Status = entity.Retrying
+		response.Artifacts = nil
+		if send(ctx, cr, response) != nil {
+			return
+		}
+
+		timer := time.NewTimer(c.jobPolicy.Delay(attempt - checkpoint.Attempts))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// send reports the progress of a crawl, giving up once ctx is done as nobody listens to it anymore
+func send(ctx context.Context, cr chan Response, res Response) error {
+	select {
+	case cr <- res:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryStep runs a step of the crawl with the step policy, reporting each retry with the progress so far
+// so the crawl isn't taken for idle while its step is retried
+func (c *cometScraper) retryStep(ctx context.Context, res Response, cr chan Response, fn func() error) error {
+	policy := c.stepPolicy
+	policy.Retried = func(attempt int, err error) {
+		res.Status = entity.Retrying
+		_ = send(ctx, cr, res)
+	}
+
+	return policy.Do(ctx, fn)
+}
+
+// attempt runs the crawl in a fresh browser, leaving the failure in res for the caller to report
+func (c *cometScraper) attempt(ctx context.Context, credentials Credentials, ap applicant.Applicant, res *Response, start time.Time, cr chan Response) (err error) {
+	ctx, span := tracing.Start(ctx, "crawler.attempt", trace.WithAttributes(attribute.Int("comet.attempt", res.Attempts)))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := chromedp.NewContext(
-		context.Background(),
+		ctx,
 	)
 
 	defer cancel()
 
-	response := Response{
-		Uuid:      id,
-		Status:    entity.Start,
-		Applicant: *c.applicant.Get(),
-	}
+	metrics.ActiveBrowsers.Inc()
+	defer metrics.ActiveBrowsers.Dec()
 
-	c.crawl(ctx, credentials, response, cr, done)
+	return c.crawl(ctx, credentials, ap, res, start, cr)
 }
 
-func (c *cometScraper) crawl(ctx context.Context, credentials Credentials, res Response, cr chan Response, done chan struct{}) {
-	start := time.Now()
-	err := c.login(ctx, credentials)
+// authenticate logs in, or reuses the account's session, going through the verification challenge when there is one
+func (c *cometScraper) authenticate(ctx context.Context, credentials Credentials, res *Response, start time.Time, cr chan Response) error {
+	err := c.retryStep(ctx, *res, cr, func() error {
+		return c.login(ctx, credentials)
+	})
+	if errors.Is(err, ErrVerificationRequired) {
+		if err = c.verify(ctx, credentials, res, start, cr); err == nil {
+			if err = c.saveSession(ctx, credentials); err != nil {
+				c.log(ctx).Warnw("could not save the session", "error", err)
+			}
+			err = nil
+		}
+	}
 	if err != nil {
-		res.Status = entity.FailedCredentials
 		res.TimeTaken = time.Since(start).String()
-		if err.Error() != entity.FailedCredentials {
+		switch {
+		case err.Error() == entity.FailedCredentials || err.Error() == entity.FailedVerification:
+			res.Status = err.Error()
+		case errors.Is(err, ErrVerificationTimeout):
+			res.Status = entity.TimeOut
+		default:
 			res.Status = entity.Fail
+			res.Artifacts = c.captureArtifacts(ctx, entity.StepLogin)
 		}
-		cr <- res
-		close(done)
-		return
+		return err
 	}
 
 	res.Status = entity.Logged
 	res.TimeTaken = time.Since(start).String()
-	cr <- res
+	return send(ctx, cr, *res)
+}
 
-	lenSkills, lenExperiences, resumeUrl, err := c.getBaseInfo(ctx)
-	if err != nil {
-		res.Status = entity.Fail
-		res.TimeTaken = time.Since(start).String()
-		cr <- res
-		close(done)
-		return
+func (c *cometScraper) crawl(ctx context.Context, credentials Credentials, ap applicant.Applicant, res *Response, start time.Time, cr chan Response) error {
+	var err error
+	// public resume links are readable without an account
+	if credentials.ResumeUrl == "" {
+		ctx := context.WithValue(ctx, entity.StageKey, entity.StepLogin)
+		stageStart := time.Now()
+		if err = c.authenticate(ctx, credentials, res, start, cr); err != nil {
+			return err
+		}
+		metrics.ObserveStage(entity.StepLogin, stageStart)
 	}
 
-	res.Status = entity.Basic
-	res.TimeTaken = time.Since(start).String()
-	res.Applicant = *c.applicant.Get()
-	cr <- res
+	if res.Checkpoint.Stage == "" {
+		ctx := context.WithValue(ctx, entity.StageKey, entity.StepBaseInfo)
+		stageStart := time.Now()
+		var lenSkills, lenExperiences int
+		var resumeUrl string
+		err = c.retryStep(ctx, *res, cr, func() (err error) {
+			lenSkills, lenExperiences, resumeUrl, err = c.getBaseInfo(ctx, ap, credentials.ResumeUrl)
+			return
+		})
+		if err != nil {
+			res.Status = entity.Fail
+			res.TimeTaken = time.Since(start).String()
+			res.Artifacts = c.captureArtifacts(ctx, entity.StepBaseInfo)
+			return err
+		}
+
+		if ap.Get().ImageUrl != "" {
+			// the profile is still worth saving without its picture
+			res.Image, err = c.getImage(ctx, ap)
+			if err != nil {
+				c.log(ctx).Warnw("could not download the profile picture", "error", err)
+			}
+		}
 
-	if lenSkills+lenExperiences > 0 {
-		err = c.getSkillsAndExp(ctx, lenSkills, lenExperiences, resumeUrl)
+		res.Status = entity.Basic
+		res.TimeTaken = time.Since(start).String()
+		res.Applicant = *ap.Get()
+		res.Checkpoint = entity.Checkpoint{
+			Stage:       entity.StepBaseInfo,
+			ResumeUrl:   resumeUrl,
+			Skills:      lenSkills,
+			Experiences: lenExperiences,
+		}
+		if err = send(ctx, cr, *res); err != nil {
+			return err
+		}
+		res.Image = nil
+		metrics.ObserveStage(entity.StepBaseInfo, stageStart)
+	}
+
+	if res.Checkpoint.Skills+res.Checkpoint.Experiences > 0 {
+		ctx := context.WithValue(ctx, entity.StageKey, entity.StepSkillsAndExperiences)
+		stageStart := time.Now()
+		err = c.retryStep(ctx, *res, cr, func() error {
+			return c.getSkillsAndExp(ctx, ap, res.Checkpoint.Skills, res.Checkpoint.Experiences, res.Checkpoint.ResumeUrl)
+		})
 		if err != nil {
 			res.Status = entity.Fail
 			res.TimeTaken = time.Since(start).String()
-			cr <- res
-			close(done)
-			return
+			res.Artifacts = c.captureArtifacts(ctx, entity.StepSkillsAndExperiences)
+			return err
 		}
+		metrics.ObserveStage(entity.StepSkillsAndExperiences, stageStart)
 	}
 
+	res.Checkpoint.Stage = entity.StepSkillsAndExperiences
 	res.Status = entity.Success
-	res.Applicant = *c.applicant.Get()
+	res.Applicant = *ap.Get()
 	res.TimeTaken = time.Since(start).String()
-	cr <- res
-	close(done)
+	return send(ctx, cr, *res)
 }
@@ -1,12 +1,38 @@
 package crawler
 
 import (
+	"cometScraper/entity"
 	"cometScraper/tools/scraper/pkg/applicant"
 )
 
 type Response struct {
-	Uuid      string              `json:"uuid"`
-	Status    string              `json:"status"`
-	Applicant applicant.Candidate `json:"applicant"`
-	TimeTaken string              `json:"time_taken"`
+	Uuid       string              `json:"uuid"`
+	Status     string              `json:"status"`
+	Applicant  applicant.Candidate `json:"applicant"`
+	TimeTaken  string              `json:"time_taken"`
+	Attempts   int                 `json:"attempts"`
+	Checkpoint entity.Checkpoint   `json:"-"`
+	Image      *Image              `json:"-"`
+	Artifacts  []Artifact          `json:"-"`
+}
+
+// Checkpoint is where a previous run of the crawl stopped, along with the applicant it had collected so far
+type Checkpoint struct {
+	entity.Checkpoint
+	Applicant applicant.Candidate
+	Attempts  int
+}
+
+// Image is the candidate's profile picture, downloaded within the logged in session
+type Image struct {
+	Data        []byte
+	ContentType string
+}
+
+// Artifact is a debugging capture of the page a crawl failed on
+type Artifact struct {
+	Name        string
+	Step        string
+	Data        []byte
+	ContentType string
 }
@@ -0,0 +1,84 @@
+package crawler
+
+import (
+	"cometScraper/entity"
+	"cometScraper/utils/logger"
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy tells how often, and how long apart, a failing crawl is tried again
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// Retryable reports whether an error is worth another attempt, IsTransient when nil
+	Retryable func(err error) bool
+	// Logger logs the failed attempts, they aren't logged when nil
+	Logger logger.Logger
+	// Retried is called before waiting for each retry, such as to report the crawl is still alive
+	Retried func(attempt int, err error)
+}
+
+// DefaultStepPolicy retries a single step of the crawl, such as the login or a navigation
+var DefaultStepPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 2 * time.Second, MaxBackoff: 10 * time.Second}
+
+// DefaultJobPolicy restarts the whole crawl in a fresh browser once its steps gave up
+var DefaultJobPolicy = RetryPolicy{MaxAttempts: 2, Backoff: 5 * time.Second, MaxBackoff: 30 * time.Second}
+
+// IsTransient reports whether an error may go away on its own, wrong credentials, verification
+// challenges and cancellations never do
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, ErrVerificationRequired) || errors.Is(err, ErrVerificationTimeout) {
+		return false
+	}
+
+	return err.Error() != entity.FailedCredentials && err.Error() != entity.FailedVerification
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsTransient(err)
+	}
+	return p.Retryable(err)
+}
+
+// Delay is how long to wait after the given failed attempt, doubling each time up to MaxBackoff
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// Do runs fn until it succeeds, fails with an error that isn't retryable or runs out of attempts
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) {
+			return
+		}
+
+		if p.Logger != nil {
+			logger.WithContext(ctx, p.Logger).Warnw("attempt failed, retrying", "attempt", attempt, "error", err)
+		}
+		if p.Retried != nil {
+			p.Retried(attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.Delay(attempt)):
+		}
+	}
+}
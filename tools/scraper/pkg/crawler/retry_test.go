@@ -0,0 +1,98 @@
+package crawler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/tools/scraper/pkg/crawler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	policy := crawler.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	t.Run("retries transient errors until it succeeds", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("net::ERR_CONNECTION_RESET")
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("reports each retry", func(t *testing.T) {
+		var retried []int
+		policy := policy
+		policy.Retried = func(attempt int, err error) {
+			retried = append(retried, attempt)
+		}
+		err := policy.Do(context.Background(), func() error {
+			return errors.New("net::ERR_TIMED_OUT")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, []int{1, 2}, retried)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func() error {
+			calls++
+			return errors.New("net::ERR_TIMED_OUT")
+		})
+
+		assert.EqualError(t, err, "net::ERR_TIMED_OUT")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("does not retry wrong credentials", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func() error {
+			calls++
+			return errors.New(entity.FailedCredentials)
+		})
+
+		assert.EqualError(t, err, entity.FailedCredentials)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops waiting once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := crawler.RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}.Do(ctx, func() error {
+			calls++
+			cancel()
+			return errors.New("net::ERR_TIMED_OUT")
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := crawler.RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 2*time.Second, policy.Delay(2))
+	assert.Equal(t, 4*time.Second, policy.Delay(3))
+	assert.Equal(t, 5*time.Second, policy.Delay(4))
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, crawler.IsTransient(errors.New("net::ERR_CONNECTION_RESET")))
+	assert.False(t, crawler.IsTransient(nil))
+	assert.False(t, crawler.IsTransient(context.Canceled))
+	assert.False(t, crawler.IsTransient(errors.New(entity.FailedCredentials)))
+	assert.False(t, crawler.IsTransient(errors.New(entity.FailedVerification)))
+	assert.False(t, crawler.IsTransient(crawler.ErrVerificationRequired))
+	assert.False(t, crawler.IsTransient(crawler.ErrVerificationTimeout))
+}
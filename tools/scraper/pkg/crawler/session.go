@@ -0,0 +1,170 @@
+package crawler
+
+import (
+	"cometScraper/entity"
+	"cometScraper/tools/scraper/pkg/element"
+	"cometScraper/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// sessionTTL is how long the cookies of a login are reused before logging in again
+const sessionTTL = 24 * time.Hour
+
+// SessionStore keeps the cookies of the accounts crawled, so their next crawl can skip the login
+type SessionStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, exp time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// sessionKey identifies an account without storing its email in clear
+func sessionKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "cometSession:" + hex.EncodeToString(sum[:])
+}
+
+// sessionSecret derives the key the cookies are encrypted with from the credentials,
+// so a session can only be reused by whoever knows them
+func sessionSecret(credentials Credentials) string {
+	sum := sha256.Sum256([]byte(credentials.Email + ":" + credentials.Pass))
+	return hex.EncodeToString(sum[:])
+}
+
+func GetActionsRestoreSession(elements element.Elements, cookies []*network.CookieParam, currentUrl *string) []chromedp.Action {
+	return []chromedp.Action{
+		network.SetCookies(cookies),
+		chromedp.Navigate(elements.GetUrls().FreelancerDashboard),
+		chromedp.Sleep(3 * time.Second),
+		chromedp.Location(currentUrl),
+	}
+}
+
+// cookieParams turn the cookies read from a browser into the ones to set in another
+func cookieParams(cookies []*network.Cookie) []*network.CookieParam {
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		param := &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: cookie.SameSite,
+			Priority: cookie.Priority,
+		}
+		if !cookie.Session {
+			expires := cdp.TimeSinceEpoch(time.Unix(int64(cookie.Expires), 0))
+			param.Expires = &expires
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// Cookie is a cookie of a logged in Comet session, given along with the crawl
+type Cookie struct {
+	Name     string
+	Value    string
+	Domain   string
+	Path     string
+	Secure   bool
+	HTTPOnly bool
+}
+
+// useSession sets the cookies of a session handed over by the account's owner, failing as wrong credentials
+// when they don't open the dashboard since there is no password to log in with
+func (c *cometScraper) useSession(ctx context.Context, cookies []Cookie) error {
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		params = append(params, &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+		})
+	}
+
+	var currentUrl string
+	err := c.run(ctx, "use_session", GetActionsRestoreSession(c.elements, params, &currentUrl)...)
+	if err != nil {
+		return err
+	}
+
+	if currentUrl != c.elements.GetUrls().FreelancerDashboard {
+		return errors.New(entity.FailedCredentials)
+	}
+	return nil
+}
+
+// saveSession stores the cookies of the browser once logged in
+func (c *cometScraper) saveSession(ctx context.Context, credentials Credentials) error {
+	if c.sessions == nil {
+		return nil
+	}
+
+	var cookies []*network.Cookie
+	err := c.run(ctx, "save_session", chromedp.ActionFunc(func(ctx context.Context) (err error) {
+		cookies, err = network.GetAllCookies().Do(ctx)
+		return
+	}))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return err
+	}
+
+	encrypted := utils.Encrypt(sessionSecret(credentials), string(data))
+	if encrypted == "" {
+		return errors.New("could not encrypt the session")
+	}
+
+	return c.sessions.Set(ctx, sessionKey(credentials.Email), encrypted, sessionTTL)
+}
+
+// restoreSession sets the cookies of the account's last login, reporting whether they still open the dashboard.
+// Sessions that don't are dropped, so the login that follows stores a fresh one. Sessions that can't be read,
+// as when the password given is wrong, are kept: the login goes on and replaces them once it succeeds.
+func (c *cometScraper) restoreSession(ctx context.Context, credentials Credentials) bool {
+	if c.sessions == nil {
+		return false
+	}
+
+	key := sessionKey(credentials.Email)
+	encrypted, err := c.sessions.Get(ctx, key)
+	if err != nil || encrypted == "" {
+		return false
+	}
+
+	var cookies []*network.Cookie
+	if err = json.Unmarshal([]byte(utils.Decrypt(sessionSecret(credentials), encrypted)), &cookies); err != nil {
+		c.log(ctx).Infow("could not read the stored session, logging in", "error", err)
+		return false
+	}
+
+	var currentUrl string
+	err = c.run(ctx, "restore_session", GetActionsRestoreSession(c.elements, cookieParams(cookies), &currentUrl)...)
+	if err != nil || currentUrl != c.elements.GetUrls().FreelancerDashboard {
+		c.log(ctx).Infow("stored session expired, logging in")
+		_ = c.sessions.Delete(ctx, key)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"cometScraper/entity"
+	"cometScraper/tools/scraper/pkg/element"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// VerificationTimeout is how long a challenged login waits for its code
+	VerificationTimeout = 10 * time.Minute
+	// maxVerificationCodes is how many codes are tried before giving up on the login
+	maxVerificationCodes = 3
+)
+
+var (
+	// ErrVerificationRequired is returned by the login when Comet asks for a verification code
+	ErrVerificationRequired = errors.New("verification required")
+	// ErrVerificationTimeout is returned when no code was submitted in time
+	ErrVerificationTimeout = errors.New("no verification code was submitted in time")
+)
+
+// challengeScript resolves to whether the verification code input is on the page
+const challengeScript = `document.querySelector(%q) !== null`
+
+func GetActionsChallenge(elements element.Elements, challenged *bool) []chromedp.Action {
+	return []chromedp.Action{
+		chromedp.Evaluate(fmt.Sprintf(challengeScript, elements.GetInputs().VerificationCode), challenged),
+	}
+}
+
+func GetActionsVerify(elements element.Elements, code string, currentUrl *string) []chromedp.Action {
+	return []chromedp.Action{
+		chromedp.SendKeys(elements.GetInputs().VerificationCode, code, chromedp.ByQuery),
+		chromedp.Sleep(1 * time.Second),
+		chromedp.Click(elements.GetButtons().Verify, chromedp.ByQuery),
+		chromedp.Sleep(5 * time.Second),
+		chromedp.Location(currentUrl),
+	}
+}
+
+// challenged reports whether the page the login landed on asks for a verification code
+func (c *cometScraper) challenged(ctx context.Context) (bool, error) {
+	if c.elements.GetInputs().VerificationCode == "" {
+		return false, nil
+	}
+
+	var challenged bool
+	err := c.run(ctx, "challenge", GetActionsChallenge(c.elements, &challenged)...)
+	return challenged, err
+}
+
+// verify pauses the crawl until the user submits the code Comet sent them, then types it in.
+// Wrong codes leave the challenge on the page, so the user is asked again a few times.
+func (c *cometScraper) verify(ctx context.Context, credentials Credentials, res *Response, start time.Time, cr chan Response) error {
+	if credentials.Codes == nil {
+		return ErrVerificationRequired
+	}
+
+	for i := 0; i < maxVerificationCodes; i++ {
+		res.Status = entity.AwaitingVerification
+		res.TimeTaken = time.Since(start).String()
+		if err := send(ctx, cr, *res); err != nil {
+			return err
+		}
+
+		var code string
+		select {
+		case code = <-credentials.Codes:
+		case <-time.After(VerificationTimeout):
+			return ErrVerificationTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		var currentUrl string
+		if err := c.run(ctx, "verify", GetActionsVerify(c.elements, code, &currentUrl)...); err != nil {
+			return err
+		}
+
+		if currentUrl == c.elements.GetUrls().FreelancerDashboard {
+			return nil
+		}
+	}
+
+	return errors.New(entity.FailedVerification)
+}
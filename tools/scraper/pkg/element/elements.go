@@ -27,14 +27,16 @@ type Urls struct {
 }
 
 type Inputs struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email            string `json:"email"`
+	Password         string `json:"password"`
+	VerificationCode string `json:"verificationCode"`
 }
 
 type Buttons struct {
 	Resume       string `json:"resume"`
 	AcceptCookie string `json:"acceptCookie"`
 	Login        string `json:"login"`
+	Verify       string `json:"verify"`
 }
 
 type ResumeSection struct {
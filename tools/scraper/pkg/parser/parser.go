@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a span of time scraped from a profile, End is nil while it is still ongoing
+type Period struct {
+	Start time.Time
+	End   *time.Time
+}
+
+var accents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i",
+	"ô", "o", "ö", "o",
+	"ù", "u", "û", "u", "ü", "u",
+	"ç", "c",
+	"’", "'",
+	"·", " ",
+	"(", " ", ")", " ",
+)
+
+// months maps the French and English month names, and their abbreviations, to their month
+var months = map[string]time.Month{
+	"janvier": time.January, "janv": time.January, "january": time.January, "jan": time.January,
+	"fevrier": time.February, "fevr": time.February, "fev": time.February, "february": time.February, "feb": time.February,
+	"mars": time.March, "march": time.March, "mar": time.March,
+	"avril": time.April, "avr": time.April, "april": time.April, "apr": time.April,
+	"mai": time.May, "may": time.May,
+	"juin": time.June, "june": time.June, "jun": time.June,
+	"juillet": time.July, "juil": time.July, "july": time.July, "jul": time.July,
+	"aout": time.August, "august": time.August, "aug": time.August,
+	"septembre": time.September, "september": time.September, "sept": time.September, "sep": time.September,
+	"octobre": time.October, "october": time.October, "oct": time.October,
+	"novembre": time.November, "november": time.November, "nov": time.November,
+	"decembre": time.December, "december": time.December, "dec": time.December,
+}
+
+var ongoing = map[string]bool{
+	"aujourd'hui": true, "aujourdhui": true, "present": true, "actuel": true, "actuellement": true,
+	"en cours": true, "now": true, "today": true, "current": true, "currently": true,
+}
+
+var (
+	durationRegex  = regexp.MustCompile(`\b(\d+(?:[.,]\d+)?|une|un|one|an|a)\s*\+?\s*(annees|annee|ans|an|years|year|yrs|yr|mois|months|month|mos|mo)\b`)
+	numberRegex    = regexp.MustCompile(`^\D*(\d+(?:[.,]\d+)?)\D*$`)
+	separatorRegex = regexp.MustCompile(`\s+(?:-|–|—|a|au|to|until|jusqu'a|jusqu'au)\s+|\s*[-–—]\s*`)
+	sinceRegex     = regexp.MustCompile(`^(?:depuis|since)\s+`)
+	fromRegex      = regexp.MustCompile(`^(?:de|du|from)\s+`)
+	monthYearRegex = regexp.MustCompile(`^([a-z]+)\.?\s+(\d{4})$`)
+	slashDateRegex = regexp.MustCompile(`^(\d{1,2})/(\d{4})$`)
+	yearRegex      = regexp.MustCompile(`^(\d{4})$`)
+)
+
+func normalize(s string) string {
+	s = accents.Replace(strings.ToLower(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func parseNumber(s string) (float64, bool) {
+	switch s {
+	case "un", "une", "one", "a", "an":
+		return 1, true
+	}
+
+	n, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return n, err == nil
+}
+
+// ParseDuration turn a duration such as "2 ans et 6 mois", "3 years" or "(8 mois)" into months.
+// Bounds such as "< 1 an" or "5+ years" keep the stated value, and a bare number is read as years.
+func ParseDuration(s string) (int, bool) {
+	s = normalize(s)
+	if s == "" {
+		return 0, false
+	}
+
+	matches := durationRegex.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		bare := numberRegex.FindStringSubmatch(s)
+		if bare == nil {
+			return 0, false
+		}
+		years, ok := parseNumber(bare[1])
+		return int(math.Round(years * 12)), ok
+	}
+
+	total := 0.0
+	for _, match := range matches {
+		n, ok := parseNumber(match[1])
+		if !ok {
+			return 0, false
+		}
+
+		if strings.HasPrefix(match[2], "mo") {
+			total += n
+		} else {
+			total += n * 12
+		}
+	}
+
+	return int(math.Round(total)), true
+}
+
+// ParseYears turn a duration such as "7 ans" into a number of years
+func ParseYears(s string) (float64, bool) {
+	months, ok := ParseDuration(s)
+	if !ok {
+		return 0, false
+	}
+
+	return float64(months) / 12, true
+}
+
+// parseDate read "mars 2020", "Jan. 2019", "03/2020" or "2020" as the first day of that month
+func parseDate(s string) (time.Time, bool) {
+	if match := monthYearRegex.FindStringSubmatch(s); match != nil {
+		month, ok := months[match[1]]
+		if !ok {
+			return time.Time{}, false
+		}
+		year, _ := strconv.Atoi(match[2])
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	if match := slashDateRegex.FindStringSubmatch(s); match != nil {
+		month, _ := strconv.Atoi(match[1])
+		year, _ := strconv.Atoi(match[2])
+		if month < 1 || month > 12 {
+			return time.Time{}, false
+		}
+		return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	if match := yearRegex.FindStringSubmatch(s); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	return time.Time{}, false
+}
+
+// ParsePeriod turn a period such as "janv. 2019 - Aujourd'hui", "Jan 2019 to Mar 2020"
+// or "Depuis 2018" into its start and end months
+func ParsePeriod(s string) (period Period, ok bool) {
+	s = normalize(s)
+	if since := sinceRegex.FindString(s); since != "" {
+		period.Start, ok = parseDate(s[len(since):])
+		return
+	}
+	s = fromRegex.ReplaceAllString(s, "")
+
+	parts := separatorRegex.Split(s, 2)
+	if period.Start, ok = parseDate(parts[0]); !ok {
+		return
+	}
+
+	if len(parts) == 1 {
+		end := period.Start
+		period.End = &end
+		return
+	}
+
+	if ongoing[parts[1]] {
+		return
+	}
+
+	end, ok := parseDate(parts[1])
+	if !ok || end.Before(period.Start) {
+		return Period{}, false
+	}
+	period.End = &end
+
+	return
+}
+
+// Months count the months the period covers, both ends included, up to now when it is ongoing
+func (p Period) Months(now time.Time) int {
+	end := now
+	if p.End != nil {
+		end = *p.End
+	}
+
+	count := (end.Year()-p.Start.Year())*12 + int(end.Month()) - int(p.Start.Month()) + 1
+	if count < 0 {
+		return 0
+	}
+
+	return count
+}
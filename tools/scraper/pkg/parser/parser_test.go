@@ -0,0 +1,125 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"cometScraper/tools/scraper/pkg/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(year int, m time.Month) *time.Time {
+	t := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in     string
+		months int
+		ok     bool
+	}{
+		{"5 ans", 60, true},
+		{"1 an", 12, true},
+		{"· 3 ans", 36, true},
+		{"6 mois", 6, true},
+		{"(8 mois)", 8, true},
+		{"2 ans et 6 mois", 30, true},
+		{"1 an 2 mois", 14, true},
+		{"3 années", 36, true},
+		{"moins d'un an", 12, true},
+		{"3 years", 36, true},
+		{"1 year 4 months", 16, true},
+		{"5+ years", 60, true},
+		{"1.5 years", 18, true},
+		{"1,5 an", 18, true},
+		{"7", 84, true},
+		{"", 0, false},
+		{"junior", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			months, ok := parser.ParseDuration(tt.in)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.months, months)
+		})
+	}
+}
+
+func TestParseYears(t *testing.T) {
+	tests := []struct {
+		in    string
+		years float64
+		ok    bool
+	}{
+		{"7 ans", 7, true},
+		{"10 years", 10, true},
+		{"18 mois", 1.5, true},
+		{"n/a", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			years, ok := parser.ParseYears(tt.in)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.years, years)
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in    string
+		start *time.Time
+		end   *time.Time
+		ok    bool
+	}{
+		{"janvier 2019 - mars 2020", month(2019, time.January), month(2020, time.March), true},
+		{"janv. 2019 - Aujourd'hui", month(2019, time.January), nil, true},
+		{"Févr. 2018 – Août 2019", month(2018, time.February), month(2019, time.August), true},
+		{"De septembre 2017 à décembre 2018", month(2017, time.September), month(2018, time.December), true},
+		{"Depuis mai 2021", month(2021, time.May), nil, true},
+		{"Jan 2019 - Present", month(2019, time.January), nil, true},
+		{"Sept. 2016 to Jun. 2017", month(2016, time.September), month(2017, time.June), true},
+		{"Since March 2020", month(2020, time.March), nil, true},
+		{"03/2015 - 11/2016", month(2015, time.March), month(2016, time.November), true},
+		{"2012 - 2014", month(2012, time.January), month(2014, time.January), true},
+		{"2020", month(2020, time.January), month(2020, time.January), true},
+		{"mars 2020 - janvier 2019", nil, nil, false},
+		{"13/2015 - 11/2016", nil, nil, false},
+		{"quelque temps", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			period, ok := parser.ParsePeriod(tt.in)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+			assert.Equal(t, *tt.start, period.Start)
+			assert.Equal(t, tt.end, period.End)
+		})
+	}
+}
+
+func TestPeriodMonths(t *testing.T) {
+	now := time.Date(2022, time.June, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		period parser.Period
+		months int
+	}{
+		{"same month", parser.Period{Start: *month(2020, time.March), End: month(2020, time.March)}, 1},
+		{"closed", parser.Period{Start: *month(2019, time.January), End: month(2020, time.March)}, 15},
+		{"ongoing", parser.Period{Start: *month(2021, time.June)}, 13},
+		{"starts after now", parser.Period{Start: *month(2023, time.January)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.months, tt.period.Months(now))
+		})
+	}
+}
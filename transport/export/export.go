@@ -0,0 +1,219 @@
+package export
+
+import (
+	"bytes"
+	"cometScraper/entity"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	htmlTemplate "html/template"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+	"time"
+)
+
+const (
+	JSONResume string = "jsonresume"
+	Markdown          = "markdown"
+	CSV               = "csv"
+	HTML              = "html"
+)
+
+// Formats lists every format a candidate can be exported to
+var Formats = []interface{}{JSONResume, Markdown, CSV, HTML}
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+//go:embed templates
+var templates embed.FS
+
+// Document is a rendered export, ready to be sent as a file
+type Document struct {
+	Body        []byte
+	ContentType string
+	Extension   string
+}
+
+// Renderer represent the candidate export contract
+type Renderer interface {
+	Render(format string, comet entity.CometScraper) (Document, error)
+}
+
+type renderer struct {
+	markdown *textTemplate.Template
+	html     *htmlTemplate.Template
+}
+
+// NewRenderer will create new a Renderer, the templates paths override the embedded ones when set
+func NewRenderer(markdownPath, htmlPath string) (Renderer, error) {
+	markdownSource, err := readTemplate(markdownPath, "templates/cv.md.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	htmlSource, err := readTemplate(htmlPath, "templates/cv.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	markdown, err := textTemplate.New("markdown").Parse(markdownSource)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := htmlTemplate.New("html").Parse(htmlSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderer{
+		markdown: markdown,
+		html:     html,
+	}, nil
+}
+
+func readTemplate(path, fallback string) (string, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if path != "" {
+		content, err = os.ReadFile(path)
+	} else {
+		content, err = templates.ReadFile(fallback)
+	}
+
+	return string(content), err
+}
+
+// ImagePath is the path the API serves a crawl's stored profile picture at, the scraped url expiring
+func ImagePath(id string) string {
+	return "/api/v1/comet/" + id + "/image"
+}
+
+func (r *renderer) Render(format string, comet entity.CometScraper) (document Document, err error) {
+	var buf bytes.Buffer
+
+	if comet.Applicant.ImageUrl != "" {
+		comet.Applicant.ImageUrl = ImagePath(comet.Uuid)
+	}
+
+	switch format {
+	case JSONResume:
+		document.ContentType, document.Extension = "application/json", "json"
+		err = json.NewEncoder(&buf).Encode(newResume(comet))
+	case Markdown:
+		document.ContentType, document.Extension = "text/markdown; charset=utf-8", "md"
+		err = r.markdown.Execute(&buf, comet)
+	case HTML:
+		document.ContentType, document.Extension = "text/html; charset=utf-8", "html"
+		err = r.html.Execute(&buf, comet)
+	case CSV:
+		document.ContentType, document.Extension = "text/csv; charset=utf-8", "csv"
+		err = writeCSV(&buf, comet)
+	default:
+		err = ErrUnknownFormat
+	}
+
+	document.Body = buf.Bytes()
+	return
+}
+
+// resume follows https://jsonresume.org/schema/
+type resume struct {
+	Basics resumeBasics  `json:"basics"`
+	Work   []resumeWork  `json:"work"`
+	Skills []resumeSkill `json:"skills"`
+}
+
+type resumeBasics struct {
+	Name    string `json:"name"`
+	Label   string `json:"label,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type resumeWork struct {
+	Position   string   `json:"position"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type resumeSkill struct {
+	Name  string `json:"name"`
+	Level string `json:"level,omitempty"`
+}
+
+func resumeDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01")
+}
+
+func newResume(comet entity.CometScraper) resume {
+	candidate := comet.Applicant
+	r := resume{
+		Basics: resumeBasics{
+			Name:    candidate.Name,
+			Label:   candidate.Role,
+			Image:   candidate.ImageUrl,
+			Summary: candidate.Description,
+		},
+		Work:   []resumeWork{},
+		Skills: []resumeSkill{},
+	}
+
+	for _, job := range candidate.Experience {
+		work := resumeWork{
+			Position:  job.Title,
+			StartDate: resumeDate(job.Start),
+			EndDate:   resumeDate(job.End),
+			Summary:   job.Desc,
+		}
+		if job.Skill != "" {
+			work.Highlights = []string{job.Skill}
+		}
+		r.Work = append(r.Work, work)
+	}
+
+	for _, skill := range candidate.Skill {
+		r.Skills = append(r.Skills, resumeSkill{Name: skill.Name, Level: skill.Time})
+	}
+
+	return r
+}
+
+// csvCell keeps a scraped value from being run as a formula by the spreadsheet the export is opened in
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// writeCSV flattens the candidate into one row per profile, experience and skill
+func writeCSV(buf *bytes.Buffer, comet entity.CometScraper) error {
+	candidate := comet.Applicant
+	w := csv.NewWriter(buf)
+	rows := [][]string{
+		{"uuid", "section", "name", "detail", "description", "period", "start", "end", "months"},
+		{comet.Uuid, "profile", csvCell(candidate.Name), csvCell(candidate.Role), csvCell(candidate.Description), csvCell(candidate.TimeOfExperience), "", "", strconv.Itoa(int(math.Round(candidate.YearsOfExperience * 12)))},
+	}
+
+	for _, job := range candidate.Experience {
+		rows = append(rows, []string{comet.Uuid, "experience", csvCell(job.Title), csvCell(job.Skill), csvCell(job.Desc), csvCell(job.Period), resumeDate(job.Start), resumeDate(job.End), strconv.Itoa(job.Months)})
+	}
+
+	for _, skill := range candidate.Skill {
+		rows = append(rows, []string{comet.Uuid, "skill", csvCell(skill.Name), "", "", csvCell(skill.Time), "", "", strconv.Itoa(skill.Months)})
+	}
+
+	return w.WriteAll(rows)
+}
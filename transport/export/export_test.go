@@ -0,0 +1,132 @@
+package export_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/tools/scraper/pkg/applicant"
+	"cometScraper/transport/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCometScraper() entity.CometScraper {
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return entity.CometScraper{
+		Uuid: "b7b5d3ac-4c4f-4d8e-9c5a-0e7c2b3f1a2d",
+		Applicant: applicant.Candidate{
+			Name:             "Ada Lovelace",
+			Role:             "Backend <Developer>",
+			TimeOfExperience: "7 ans",
+			Experience: []applicant.Job{
+				{Title: "Go Developer", Skill: "Golang", Desc: "APIs, workers", Period: "janv. 2019 - Aujourd'hui", Start: &start, Months: 12},
+			},
+			Skill: []applicant.Skill{
+				{Name: "Go", Time: "5 ans", Months: 60},
+			},
+			YearsOfExperience: 7,
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	r, err := export.NewRenderer("", "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		format      string
+		contentType string
+		contains    []string
+	}{
+		{export.Markdown, "text/markdown; charset=utf-8", []string{"# Ada Lovelace", "### Go Developer", "- Go (5 ans)"}},
+		{export.HTML, "text/html; charset=utf-8", []string{"<h1>Ada Lovelace</h1>", "Backend &lt;Developer&gt;", "<li>Go (5 ans)</li>"}},
+		{export.CSV, "text/csv; charset=utf-8", []string{"uuid,section,name", "experience,Go Developer,Golang,\"APIs, workers\"", "skill,Go,,,5 ans,,,60"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			document, err := r.Render(tt.format, newCometScraper())
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, document.ContentType)
+			for _, s := range tt.contains {
+				assert.Contains(t, string(document.Body), s)
+			}
+		})
+	}
+}
+
+func TestRenderJSONResume(t *testing.T) {
+	r, err := export.NewRenderer("", "")
+	require.NoError(t, err)
+
+	document, err := r.Render(export.JSONResume, newCometScraper())
+	require.NoError(t, err)
+
+	var resume map[string]interface{}
+	require.NoError(t, json.Unmarshal(document.Body, &resume))
+	assert.Equal(t, "Ada Lovelace", resume["basics"].(map[string]interface{})["name"])
+	work := resume["work"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "2019-01", work["startDate"])
+	assert.NotContains(t, work, "endDate")
+}
+
+func TestRenderCSVFormulas(t *testing.T) {
+	r, err := export.NewRenderer("", "")
+	require.NoError(t, err)
+
+	comet := newCometScraper()
+	comet.Applicant.Name = "=HYPERLINK(\"https://evil.example\")"
+	comet.Applicant.Role = "+33 6 12 34 56 78"
+	comet.Applicant.Skill = []applicant.Skill{{Name: "@SUM(A1:A2)", Time: "-1"}}
+
+	document, err := r.Render(export.CSV, comet)
+	require.NoError(t, err)
+	body := string(document.Body)
+	assert.Contains(t, body, `"'=HYPERLINK(""https://evil.example"")",'+33 6 12 34 56 78`)
+	assert.Contains(t, body, "skill,'@SUM(A1:A2),,,'-1,,,0")
+}
+
+func TestRenderStoredImage(t *testing.T) {
+	r, err := export.NewRenderer("", "")
+	require.NoError(t, err)
+
+	comet := newCometScraper()
+	comet.Applicant.ImageUrl = "https://cdn.comet.co/avatar.jpg?Expires=1700000000&Signature=abc"
+	image := "/api/v1/comet/" + comet.Uuid + "/image"
+
+	document, err := r.Render(export.HTML, comet)
+	require.NoError(t, err)
+	assert.Contains(t, string(document.Body), `<img src="`+image+`"`)
+	assert.NotContains(t, string(document.Body), "cdn.comet.co")
+
+	document, err = r.Render(export.JSONResume, comet)
+	require.NoError(t, err)
+	var resume map[string]interface{}
+	require.NoError(t, json.Unmarshal(document.Body, &resume))
+	assert.Equal(t, image, resume["basics"].(map[string]interface{})["image"])
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	r, err := export.NewRenderer("", "")
+	require.NoError(t, err)
+
+	_, err = r.Render("pdf", newCometScraper())
+	assert.Equal(t, export.ErrUnknownFormat, err)
+}
+
+func TestRenderTemplateOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cv.md.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("CV of {{ .Applicant.Name }}"), 0o600))
+
+	r, err := export.NewRenderer(path, "")
+	require.NoError(t, err)
+
+	document, err := r.Render(export.Markdown, newCometScraper())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(document.Body), "CV of Ada Lovelace"))
+}
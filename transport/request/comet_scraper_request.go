@@ -1,20 +1,227 @@
 package request
 
 import (
+	"errors"
+	"regexp"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/transport/export"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/robfig/cron/v3"
 )
 
-// CreateCometScraperReq represent create comet request body
+// cometUrlRegex keeps the crawler on Comet's own pages
+var cometUrlRegex = regexp.MustCompile(`^https://([a-z0-9-]+\.)*comet\.co/`)
+
+// cometDomainRegex keeps the session cookies to Comet's own domains
+var cometDomainRegex = regexp.MustCompile(`^\.?([a-z0-9-]+\.)*comet\.co$`)
+
+// maxSessionCookies bounds the cookies of a session handed over
+const maxSessionCookies = 50
+
+// CreateCometScraperReq represent create comet request body, either the account's credentials, the cookies of
+// one of its logged in sessions or a public resume link
 type CreateCometScraperReq struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email     string          `json:"email"`
+	Password  string          `json:"password"`
+	ResumeUrl string          `json:"resume_url"`
+	Cookies   []SessionCookie `json:"cookies,omitempty"`
 }
 
 func (request CreateCometScraperReq) Validate() error {
+	if request.ResumeUrl != "" {
+		return validation.ValidateStruct(
+			&request,
+			validation.Field(&request.ResumeUrl, is.URL, validation.Match(cometUrlRegex).Error("must be a Comet link")),
+		)
+	}
+
+	if len(request.Cookies) > 0 {
+		return validation.ValidateStruct(
+			&request,
+			validation.Field(&request.Cookies, validation.Length(1, maxSessionCookies)),
+		)
+	}
+
 	return validation.ValidateStruct(
 		&request,
 		validation.Field(&request.Email, validation.Required, is.Email),
 		validation.Field(&request.Password, validation.Required),
 	)
 }
+
+// SessionCookie represent a cookie of a logged in Comet session
+type SessionCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path"`
+	Secure   bool   `json:"secure"`
+	HttpOnly bool   `json:"http_only"`
+}
+
+func (cookie SessionCookie) Validate() error {
+	return validation.ValidateStruct(
+		&cookie,
+		validation.Field(&cookie.Name, validation.Required),
+		validation.Field(&cookie.Value, validation.Required),
+		validation.Field(&cookie.Domain, validation.Required, validation.Match(cometDomainRegex).Error("must be a Comet domain")),
+	)
+}
+
+// CreateBatchCometScraperReq represent create comet batch request body, one item per account or resume link
+type CreateBatchCometScraperReq struct {
+	Items []CreateCometScraperReq `json:"items"`
+}
+
+func (request CreateBatchCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Items, validation.Required, validation.Length(1, entity.MaxBatchSize)),
+	)
+}
+
+// VerificationCometScraperReq represent the verification code the user received when the login was challenged
+type VerificationCometScraperReq struct {
+	Code string `json:"code"`
+}
+
+func (request VerificationCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Code, validation.Required, validation.Length(1, 32)),
+	)
+}
+
+// FetchCometScraperReq represent fetch comet query params
+type FetchCometScraperReq struct {
+	Cursor      string `query:"cursor"`
+	Limit       int    `query:"limit"`
+	Status      string `query:"status"`
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	SortBy      string `query:"sort_by"`
+	SortOrder   string `query:"sort_order"`
+}
+
+func (request FetchCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(entity.MaxFetchLimit)),
+		validation.Field(&request.CreatedFrom, validation.Date(time.RFC3339)),
+		validation.Field(&request.CreatedTo, validation.Date(time.RFC3339)),
+		validation.Field(&request.SortBy, validation.In(entity.SortByCreatedAt, entity.SortByUpdatedAt)),
+		validation.Field(&request.SortOrder, validation.In(entity.SortOrderAsc, entity.SortOrderDesc)),
+	)
+}
+
+// SearchCometScraperReq represent search comet query params
+type SearchCometScraperReq struct {
+	Q     string `query:"q"`
+	Limit int    `query:"limit"`
+}
+
+func (request SearchCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Q, validation.Required, validation.Length(1, entity.MaxSearchLength)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(entity.MaxFetchLimit)),
+	)
+}
+
+// DiffCandidateReq represent candidate diff query params, the versions compared
+type DiffCandidateReq struct {
+	From int `query:"from"`
+	To   int `query:"to"`
+}
+
+func (request DiffCandidateReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.From, validation.Min(1)),
+		validation.Field(&request.To, validation.Min(1)),
+	)
+}
+
+// ExportCometScraperReq represent export comet query params
+type ExportCometScraperReq struct {
+	Format string `query:"format"`
+}
+
+func (request ExportCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Format, validation.Required, validation.In(export.Formats...)),
+	)
+}
+
+// StreamCometScraperReq represent ndjson export query params
+type StreamCometScraperReq struct {
+	Since string `query:"since"`
+}
+
+func (request StreamCometScraperReq) Validate() error {
+	return validation.ValidateStruct(
+		&request,
+		validation.Field(&request.Since, validation.Date(time.RFC3339)),
+	)
+}
+
+// CreateScheduleReq represent create schedule request body, the credentials or session cookies of the crawl
+// to re-run are stored encrypted once their owner consented to it
+type CreateScheduleReq struct {
+	CreateCometScraperReq
+	ScraperUuid string `json:"scraper_uuid"`
+	Cron        string `json:"cron"`
+	Consent     bool   `json:"consent"`
+}
+
+func (request CreateScheduleReq) Validate() error {
+	errs := validation.Errors{}
+	if credentialErrs, ok := request.CreateCometScraperReq.Validate().(validation.Errors); ok {
+		for field, err := range credentialErrs {
+			errs[field] = err
+		}
+	}
+
+	scheduleErrs, _ := validation.ValidateStruct(
+		&request,
+		validation.Field(&request.ScraperUuid, validation.Required),
+		validation.Field(&request.Cron, validation.Required, validation.By(validCron)),
+		validation.Field(&request.Consent, validation.By(consented)),
+	).(validation.Errors)
+	for field, err := range scheduleErrs {
+		errs[field] = err
+	}
+
+	return errs.Filter()
+}
+
+// consented requires the consent to be given explicitly, a missing or false one being rejected
+func consented(value interface{}) error {
+	if consent, _ := value.(bool); !consent {
+		return errors.New("the account owner must consent to the credentials or cookies being stored")
+	}
+	return nil
+}
+
+// validCron accepts the standard 5 fields cron expressions running at most once per entity.MinScheduleInterval
+func validCron(value interface{}) error {
+	schedule, err := cron.ParseStandard(value.(string))
+	if err != nil {
+		return errors.New("must be a valid cron expression")
+	}
+
+	// the runs of expressions such as "0,30 9 * * *" aren't evenly spaced, a few are compared
+	next := schedule.Next(time.Now())
+	for i := 0; i < 5; i++ {
+		following := schedule.Next(next)
+		if following.Sub(next) < entity.MinScheduleInterval {
+			return errors.New("must not run more than once an hour")
+		}
+		next = following
+	}
+	return nil
+}
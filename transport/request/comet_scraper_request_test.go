@@ -0,0 +1,69 @@
+package request_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cometScraper/transport/request"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduleReqConsent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid bool
+	}{
+		{"given", `{"email":"user@comet.co","password":"pass","scraper_uuid":"scraper","cron":"0 6 * * 1","consent":true}`, false},
+		{"refused", `{"email":"user@comet.co","password":"pass","scraper_uuid":"scraper","cron":"0 6 * * 1","consent":false}`, true},
+		{"missing", `{"email":"user@comet.co","password":"pass","scraper_uuid":"scraper","cron":"0 6 * * 1"}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req request.CreateScheduleReq
+			require.NoError(t, json.Unmarshal([]byte(test.body), &req))
+
+			err := req.Validate()
+			if !test.invalid {
+				assert.NoError(t, err)
+				return
+			}
+			errs, ok := err.(validation.Errors)
+			require.True(t, ok)
+			assert.Contains(t, errs, "consent")
+		})
+	}
+}
+
+func TestCreateCometScraperReqCookies(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid bool
+	}{
+		{"comet domain", `{"cookies":[{"name":"session","value":"token","domain":".comet.co"}]}`, false},
+		{"comet subdomain", `{"cookies":[{"name":"session","value":"token","domain":"app.comet.co"}]}`, false},
+		{"other domain", `{"cookies":[{"name":"session","value":"token","domain":"evil.com"}]}`, true},
+		{"lookalike domain", `{"cookies":[{"name":"session","value":"token","domain":"notcomet.co"}]}`, true},
+		{"no value", `{"cookies":[{"name":"session","domain":".comet.co"}]}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req request.CreateCometScraperReq
+			require.NoError(t, json.Unmarshal([]byte(test.body), &req))
+
+			err := req.Validate()
+			if !test.invalid {
+				assert.NoError(t, err)
+				return
+			}
+			errs, ok := err.(validation.Errors)
+			require.True(t, ok)
+			assert.Contains(t, errs, "cookies")
+		})
+	}
+}
@@ -3,100 +3,774 @@ package usecase
 import (
 	"cometScraper/tools/scraper/pkg/crawler"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
-	"log"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/infrastructure/tracing"
+	"cometScraper/repository/blob"
 	"cometScraper/repository/pgsql"
 	"cometScraper/repository/redis"
+	"cometScraper/tools/scraper/pkg/applicant"
+	"cometScraper/transport/export"
 	"cometScraper/transport/request"
 	"cometScraper/utils"
+	"cometScraper/utils/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // CometScraperUsecase represent the craper's usecase contract
 type CometScraperUsecase interface {
 	StartProcess(ctx context.Context, request *request.CreateCometScraperReq) (string, error)
+	StartBatch(ctx context.Context, request *request.CreateBatchCometScraperReq) (string, error)
+	GetBatch(ctx context.Context, id string) (entity.BatchProgress, error)
+	Resume(ctx context.Context, id string, request *request.CreateCometScraperReq) error
+	Recrawl(ctx context.Context, id string, request *request.CreateCometScraperReq) error
+	FetchVersions(ctx context.Context, id string) ([]entity.CometScraperVersion, error)
+	Diff(ctx context.Context, id string, request *request.DiffCandidateReq) (entity.CandidateDiff, error)
+	SubmitVerification(ctx context.Context, id string, request *request.VerificationCometScraperReq) error
 	GetByID(ctx context.Context, id string) (entity.CometScraper, error)
-	Fetch(ctx context.Context) ([]entity.CometScraper, error)
+	Fetch(ctx context.Context, request *request.FetchCometScraperReq) (entity.CometScraperPage, error)
 	Update(ctx context.Context, cometScraper *entity.CometScraper) error
-	UpsertStatus(id string, status string) error
+	UpsertStatus(ctx context.Context, id string, status string) error
+	Search(ctx context.Context, request *request.SearchCometScraperReq) ([]entity.CometScraperSearchResult, error)
+	Export(ctx context.Context, id string, request *request.ExportCometScraperReq) (export.Document, error)
+	Stream(ctx context.Context, request *request.StreamCometScraperReq, fn func(entity.CometScraper) error) error
+	GetImage(ctx context.Context, id string) ([]byte, string, error)
+	FetchArtifacts(ctx context.Context, id string) ([]entity.Artifact, error)
+	GetArtifact(ctx context.Context, id, name string) ([]byte, string, error)
 	Delete(ctx context.Context, id string) error
 	Create(ctx context.Context, cometScraper entity.CometScraper) error
+	Running() int
+	Draining() bool
+	Shutdown(ctx context.Context) error
 }
 
 type cometScraperUsecase struct {
 	cometScraperRepo pgsql.CometScraperRepository
 	redisRepo        redis.RedisRepository
 	cometCrawler     crawler.CometScraper
+	exportRenderer   export.Renderer
+	blobRepo         blob.BlobRepository
+	artifactRepo     pgsql.ArtifactRepository
+	batchRepo        pgsql.BatchRepository
+	versionRepo      pgsql.VersionRepository
+	logger           logger.Logger
+
+	// batchSlots bounds how many crawls of batches run at once, each one holding a slot
+	batchSlots chan struct{}
+
+	// codes holds, by process, the channel a crawl paused on a verification challenge reads its code from
+	codesMu sync.Mutex
+	codes   map[string]chan string
+
+	// running counts the crawls this instance started and that haven't finished yet
+	running int64
+	// crawls waits for the running crawls, interrupted through their cancel func
+	crawls    sync.WaitGroup
+	cancelsMu sync.Mutex
+	cancels   map[string]context.CancelFunc
+	// draining is set once Shutdown was called, new crawls are refused from then on
+	draining int32
 }
 
 // NewCometScraperUsecase will create new an cometScraperUsecase object representation of CometScraperUsecase interface
-func NewCometScraperUsecase(cometScraperRepo pgsql.CometScraperRepository, redisRepo redis.RedisRepository, cometCrawler crawler.CometScraper) CometScraperUsecase {
+func NewCometScraperUsecase(cometScraperRepo pgsql.CometScraperRepository, redisRepo redis.RedisRepository, cometCrawler crawler.CometScraper, exportRenderer export.Renderer, blobRepo blob.BlobRepository, artifactRepo pgsql.ArtifactRepository, batchRepo pgsql.BatchRepository, versionRepo pgsql.VersionRepository, logger logger.Logger, batchWorkers int) CometScraperUsecase {
+	if batchWorkers <= 0 {
+		batchWorkers = defaultBatchWorkers
+	}
+
 	return &cometScraperUsecase{
 		cometScraperRepo: cometScraperRepo,
 		redisRepo:        redisRepo,
 		cometCrawler:     cometCrawler,
+		exportRenderer:   exportRenderer,
+		blobRepo:         blobRepo,
+		artifactRepo:     artifactRepo,
+		batchRepo:        batchRepo,
+		versionRepo:      versionRepo,
+		logger:           logger,
+		batchSlots:       make(chan struct{}, batchWorkers),
+		codes:            make(map[string]chan string),
+		cancels:          make(map[string]context.CancelFunc),
 	}
 }
 
-func (c *cometScraperUsecase) StartProcess(ctx context.Context, request *request.CreateCometScraperReq) (string, error) {
+// idleTimeout is how long a crawl may go without reporting progress before being marked as timed out,
+// the crawler reports each retry so it only has to outlast a single attempt of a step and its backoff
+const idleTimeout = 80 * time.Second
+
+// defaultBatchWorkers is how many crawls of batches run at once when not configured
+const defaultBatchWorkers = 2
+
+// cacheKey returns the key holding the cache generation of a tenant's crawls
+func cacheKey(tenantID string) string {
+	return "cometScrapers:" + tenantID
+}
+
+// imageKey returns the blob key of a crawl's profile picture
+func imageKey(tenantID, id string) string {
+	return tenantID + "/" + id
+}
+
+// artifactKey returns the blob key of one of a crawl's failure artifacts
+func artifactKey(tenantID, id, name string) string {
+	return tenantID + "/" + id + ".artifacts/" + name
+}
+
+// newCredentials turns the body a crawl was requested with into what the crawler logs in with
+func newCredentials(request *request.CreateCometScraperReq, codes <-chan string) crawler.Credentials {
 	credentials := crawler.Credentials{
-		Email: request.Email,
-		Pass:  request.Password,
+		Email:     request.Email,
+		Pass:      request.Password,
+		ResumeUrl: request.ResumeUrl,
+		Codes:     codes,
+	}
+	for _, cookie := range request.Cookies {
+		credentials.Cookies = append(credentials.Cookies, crawler.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		})
 	}
+	return credentials
+}
 
-	cr := make(chan crawler.Response)
-	done := make(chan struct{})
-	processUuid := c.cometCrawler.GetUuid()
-	err := c.Create(ctx, entity.CometScraper{Uuid: processUuid, Status: entity.Start, TimeTaken: "0"})
+// personKey identifies the person a crawl is about by the hash of its profile url, which login and resume link
+// crawls both reach, so the versions of all their crawls follow each other. Crawls without one keep their own.
+// The versions migration normalizes and hashes the stored crawls' urls the same way, which is why only ascii
+// whitespace and letters are trimmed and lowered.
+func personKey(id string, checkpoint entity.Checkpoint) string {
+	profileUrl := strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, strings.Trim(checkpoint.ResumeUrl, " \t\n\r\f\v"))
+	if i := strings.IndexByte(profileUrl, '#'); i >= 0 {
+		profileUrl = profileUrl[:i]
+	}
+	if i := strings.IndexByte(profileUrl, '?'); i >= 0 {
+		profileUrl = profileUrl[:i]
+	}
+	profileUrl = strings.TrimRight(profileUrl, "/")
+	if profileUrl == "" {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(profileUrl))
+	return hex.EncodeToString(sum[:])
+}
+
+// encryptedNamePrefix marks the encrypted candidate names, telling them apart from those
+// the first update of older crawls saved in plaintext
+const encryptedNamePrefix = "enc:"
+
+// encryptName encrypts a crawl's candidate name with its uuid, marked as encrypted
+func encryptName(id, name string) string {
+	if name == "" {
+		return name
+	}
+
+	return encryptedNamePrefix + utils.Encrypt(id, name)
+}
+
+// decryptName returns a crawl's decrypted candidate name. Unmarked names were saved in plaintext
+// and are returned as they are, marked ones that can't be decrypted are dropped.
+func decryptName(id, name string) string {
+	if !strings.HasPrefix(name, encryptedNamePrefix) {
+		return name
+	}
+
+	return utils.Decrypt(id, strings.TrimPrefix(name, encryptedNamePrefix))
+}
+
+// queryCacheKey returns the key holding one cached listing of a tenant's crawls,
+// bound to the current generation so invalidateCache drops every query at once
+func (c *cometScraperUsecase) queryCacheKey(ctx context.Context, tenantID string, request *request.FetchCometScraperReq) string {
+	version, _ := c.redisRepo.Get(ctx, cacheKey(tenantID))
+	query := url.Values{
+		"cursor":       {request.Cursor},
+		"limit":        {strconv.Itoa(request.Limit)},
+		"status":       {request.Status},
+		"created_from": {request.CreatedFrom},
+		"created_to":   {request.CreatedTo},
+		"sort_by":      {request.SortBy},
+		"sort_order":   {request.SortOrder},
+	}
+
+	return fmt.Sprintf("%s:%s:%s", cacheKey(tenantID), version, query.Encode())
+}
+
+// invalidateCache moves a tenant's cache to a new generation
+func (c *cometScraperUsecase) invalidateCache(ctx context.Context, tenantID string) {
+	_ = c.redisRepo.Set(ctx, cacheKey(tenantID), strconv.FormatInt(time.Now().UnixNano(), 10), 0)
+}
+
+// newFilter turn the fetch query params into a repository filter
+func newFilter(request *request.FetchCometScraperReq) (filter entity.CometScraperFilter, err error) {
+	filter = entity.CometScraperFilter{
+		Status:    request.Status,
+		SortBy:    request.SortBy,
+		SortOrder: request.SortOrder,
+		Limit:     request.Limit,
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = entity.SortByCreatedAt
+	}
+	if filter.SortOrder == "" {
+		filter.SortOrder = entity.SortOrderDesc
+	}
+	if filter.Limit == 0 {
+		filter.Limit = entity.DefaultFetchLimit
+	}
+
+	if request.CreatedFrom != "" {
+		createdFrom, errParse := time.Parse(time.RFC3339, request.CreatedFrom)
+		if errParse != nil {
+			return filter, utils.NewBadRequestError("invalid created_from")
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+
+	if request.CreatedTo != "" {
+		createdTo, errParse := time.Parse(time.RFC3339, request.CreatedTo)
+		if errParse != nil {
+			return filter, utils.NewBadRequestError("invalid created_to")
+		}
+		filter.CreatedTo = &createdTo
+	}
+
+	if request.Cursor != "" {
+		cursor, errCursor := utils.DecodeCursor(request.Cursor)
+		if errCursor != nil {
+			return filter, utils.NewBadRequestError(errCursor.Error())
+		}
+		filter.After = &cursor
+	}
+
+	return
+}
+
+func (c *cometScraperUsecase) StartProcess(ctx context.Context, request *request.CreateCometScraperReq) (processUuid string, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.StartProcess")
+	defer func() { tracing.End(span, err) }()
+
+	if c.Draining() {
+		return "", utils.NewServiceUnavailableError("the instance is shutting down, please try again")
+	}
+
+	processUuid = c.cometCrawler.GetUuid()
+	span.SetAttributes(tracing.ProcessUuid.String(processUuid))
+	err = c.Create(ctx, entity.CometScraper{Uuid: processUuid, Status: entity.Start, TimeTaken: "0"})
 	if err != nil {
 		return "", utils.NewInternalServerError("Some internal error happened, please contact support")
 	}
 
-	go c.cometCrawler.StartCrawling(processUuid, credentials, cr, done)
-	go c.HandleAsync(processUuid, cr, done)
+	credentials := newCredentials(request, c.listenCodes(processUuid))
+
+	c.crawl(ctx, processUuid, credentials, crawler.Checkpoint{})
 
 	return processUuid, nil
 }
 
-func (c *cometScraperUsecase) HandleAsync(processUuid string, cr chan crawler.Response, done chan struct{}) {
-	ctx, cancel := context.WithCancel(context.Background())
+// Resume crawls a failed process again, starting after the last stage it completed
+func (c *cometScraperUsecase) Resume(ctx context.Context, id string, request *request.CreateCometScraperReq) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Resume", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	if c.Draining() {
+		return utils.NewServiceUnavailableError("the instance is shutting down, please try again")
+	}
+
+	comet, err := c.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comet.Status != entity.Fail && comet.Status != entity.TimeOut && comet.Status != entity.Interrupted {
+		return utils.NewBadRequestError("only failed processes can be resumed")
+	}
+
+	if err = c.UpsertStatus(ctx, id, entity.Start); err != nil {
+		return utils.NewInternalServerError(err)
+	}
+
+	credentials := newCredentials(request, c.listenCodes(id))
+	checkpoint := crawler.Checkpoint{
+		Checkpoint: comet.Checkpoint,
+		Applicant:  comet.Applicant,
+		Attempts:   comet.Attempts,
+	}
+
+	c.crawl(ctx, id, credentials, checkpoint)
+
+	return nil
+}
+
+// Recrawl crawls a stopped process again from scratch, its successful crawls being kept as versions
+func (c *cometScraperUsecase) Recrawl(ctx context.Context, id string, request *request.CreateCometScraperReq) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Recrawl", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	if c.Draining() {
+		return utils.NewServiceUnavailableError("the instance is shutting down, please try again")
+	}
+
+	tenantID := utils.GetTenantID(ctx)
+	comet, err := c.cometScraperRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("process not found")
+		}
+		return
+	}
+
+	if !entity.IsFinal(comet.Status) {
+		return utils.NewBadRequestError("the process is still running")
+	}
+
+	if err = c.UpsertStatus(ctx, id, entity.Start); err != nil {
+		return utils.NewInternalServerError(err)
+	}
+
+	credentials := newCredentials(request, c.listenCodes(id))
+
+	c.crawl(ctx, id, credentials, crawler.Checkpoint{})
+
+	return nil
+}
+
+// FetchVersions returns the candidates of each successful crawl of the person a process crawled, the latest first
+func (c *cometScraperUsecase) FetchVersions(ctx context.Context, id string) (versions []entity.CometScraperVersion, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.FetchVersions", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID := utils.GetTenantID(ctx)
+	comet, err := c.cometScraperRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("process not found")
+		}
+		return
+	}
+
+	versions, err = c.versionRepo.FetchByPerson(ctx, tenantID, personKey(id, comet.Checkpoint))
+	for i := range versions {
+		versions[i].Applicant.Name = decryptName(versions[i].Uuid, versions[i].Applicant.Name)
+	}
+	return
+}
+
+// Diff compares two versions of a process' candidate, by default its latest one with the one before
+func (c *cometScraperUsecase) Diff(ctx context.Context, id string, request *request.DiffCandidateReq) (diff entity.CandidateDiff, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Diff", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	versions, err := c.FetchVersions(ctx, id)
+	if err != nil {
+		return
+	}
+	if len(versions) == 0 {
+		err = utils.NewNotFoundError("the candidate has no version yet")
+		return
+	}
+
+	// versions are sorted from the latest
+	to, from := 0, 1
+	if request.To != 0 {
+		to = -1
+		for i := range versions {
+			if versions[i].Version == request.To {
+				to, from = i, i+1
+			}
+		}
+	}
+	if request.From != 0 {
+		from = -1
+		for i := range versions {
+			if versions[i].Version == request.From {
+				from = i
+			}
+		}
+	}
+
+	switch {
+	case to == -1:
+		err = utils.NewNotFoundError(fmt.Sprintf("version %d not found", request.To))
+	case from == -1:
+		err = utils.NewNotFoundError(fmt.Sprintf("version %d not found", request.From))
+	case from >= len(versions):
+		err = utils.NewBadRequestError("there is no version before, set from")
+	}
+	if err != nil {
+		return
+	}
+
+	return entity.CandidateDiff{
+		Uuid: id,
+		From: versions[from].Version,
+		To:   versions[to].Version,
+		Diff: applicant.NewDiff(versions[from].Applicant, versions[to].Applicant),
+	}, nil
+}
+
+// crawl runs the crawl in the background, in a span of the request's trace that outlives the request.
+// The returned channel is closed once the crawl is over.
+func (c *cometScraperUsecase) crawl(ctx context.Context, processUuid string, credentials crawler.Credentials, checkpoint crawler.Checkpoint) <-chan struct{} {
+	ctx = context.WithValue(tracing.Detach(ctx), entity.ProcessUuidKey, processUuid)
+	ctx, span := tracing.Start(ctx, "usecase.crawl", trace.WithAttributes(tracing.ProcessUuid.String(processUuid)))
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancelsMu.Lock()
+	c.cancels[processUuid] = cancel
+	c.cancelsMu.Unlock()
+
+	cr := make(chan crawler.Response)
+	done := make(chan struct{})
+	go c.cometCrawler.StartCrawling(ctx, processUuid, credentials, checkpoint, cr, done)
+	metrics.CrawlsInFlight.Inc()
+	atomic.AddInt64(&c.running, 1)
+	c.crawls.Add(1)
+	finished := make(chan struct{})
+	go func() {
+		defer c.crawls.Done()
+		defer close(finished)
+		defer span.End()
+		defer c.forgetCancel(processUuid)
+		c.HandleAsync(ctx, processUuid, cr, done)
+	}()
+
+	return finished
+}
+
+// StartBatch queues a crawl per item and runs them in the background, batchSlots at a time
+func (c *cometScraperUsecase) StartBatch(ctx context.Context, request *request.CreateBatchCometScraperReq) (batchUuid string, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.StartBatch")
+	defer func() { tracing.End(span, err) }()
+
+	if c.Draining() {
+		return "", utils.NewServiceUnavailableError("the instance is shutting down, please try again")
+	}
+
+	batch := entity.Batch{
+		Uuid:      c.cometCrawler.GetUuid(),
+		TenantID:  utils.GetTenantID(ctx),
+		CreatedAt: time.Now(),
+	}
+	span.SetAttributes(attribute.String("comet.batch", batch.Uuid), attribute.Int("comet.batch.size", len(request.Items)))
+
+	ids := make([]string, len(request.Items))
+	for i := range request.Items {
+		ids[i] = c.cometCrawler.GetUuid()
+		err = c.Create(ctx, entity.CometScraper{Uuid: ids[i], Status: entity.Queued, TimeTaken: "0"})
+		if err != nil {
+			return "", utils.NewInternalServerError(err)
+		}
+	}
+
+	if err = c.batchRepo.Create(ctx, &batch, ids); err != nil {
+		return "", utils.NewInternalServerError(err)
+	}
+	metrics.CrawlQueueDepth.Add(float64(len(ids)))
+
+	c.crawls.Add(1)
+	go func() {
+		defer c.crawls.Done()
+		c.runBatch(tracing.Detach(ctx), ids, request.Items)
+	}()
+
+	return batch.Uuid, nil
+}
+
+// runBatch starts the crawls of a batch in order, as slots free up. Once the instance drains,
+// the crawls not started yet are left Interrupted so they can be resumed.
+func (c *cometScraperUsecase) runBatch(ctx context.Context, ids []string, items []request.CreateCometScraperReq) {
+	for i, item := range items {
+		c.batchSlots <- struct{}{}
+		metrics.CrawlQueueDepth.Dec()
+		if c.Draining() {
+			<-c.batchSlots
+			metrics.CrawlQueueDepth.Sub(float64(len(ids) - i - 1))
+			for _, id := range ids[i:] {
+				if err := c.UpsertStatus(ctx, id, entity.Interrupted); err != nil {
+					logger.WithContext(ctx, c.logger).Errorw("could not interrupt the queued crawl", "uuid", id, "error", err)
+				}
+			}
+			return
+		}
+
+		credentials := newCredentials(&item, c.listenCodes(ids[i]))
+		finished := c.crawl(ctx, ids[i], credentials, crawler.Checkpoint{})
+		go func() {
+			<-finished
+			<-c.batchSlots
+		}()
+	}
+}
+
+func (c *cometScraperUsecase) forgetCancel(id string) {
+	c.cancelsMu.Lock()
+	defer c.cancelsMu.Unlock()
+
+	if cancel, ok := c.cancels[id]; ok {
+		cancel()
+		delete(c.cancels, id)
+	}
+}
+
+// GetBatch returns a batch with the state of each of its crawls
+func (c *cometScraperUsecase) GetBatch(ctx context.Context, id string) (progress entity.BatchProgress, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.GetBatch", trace.WithAttributes(attribute.String("comet.batch", id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID := utils.GetTenantID(ctx)
+	batch, err := c.batchRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("batch not found")
+		}
+		return
+	}
+
+	items, err := c.batchRepo.FetchItems(ctx, tenantID, id)
+	if err != nil {
+		return
+	}
+
+	return entity.NewBatchProgress(batch, items), nil
+}
+
+// Draining reports whether the instance is shutting down
+func (c *cometScraperUsecase) Draining() bool {
+	return atomic.LoadInt32(&c.draining) == 1
+}
+
+// Shutdown refuses new crawls and waits for the running ones to finish. Those still running when ctx is done
+// are interrupted, their browser closed and their status set to Interrupted so they can be resumed.
+func (c *cometScraperUsecase) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&c.draining, 1)
+
+	finished := make(chan struct{})
+	go func() {
+		c.crawls.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.cancelsMu.Lock()
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+	c.cancelsMu.Unlock()
+
+	<-finished
+	return ctx.Err()
+}
+
+func (c *cometScraperUsecase) HandleAsync(ctx context.Context, processUuid string, cr chan crawler.Response, done chan struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	defer c.forgetCodes(processUuid)
+	defer metrics.CrawlsInFlight.Dec()
+	defer atomic.AddInt64(&c.running, -1)
+
+	tenantID := utils.GetTenantID(ctx)
+	span := trace.SpanFromContext(ctx)
+	log := logger.WithContext(ctx, c.logger)
+
+	timeout := idleTimeout
 	for {
 		select {
-		case <-time.After(80 * time.Second):
-			log.Println("Time Out")
-			_ = c.UpsertStatus(processUuid, entity.TimeOut)
+		case <-time.After(timeout):
+			log.Warnw("crawl timed out", "idle", timeout.String())
+			_ = c.UpsertStatus(ctx, processUuid, entity.TimeOut)
+			metrics.ObserveCrawl(entity.TimeOut)
+			span.SetStatus(codes.Error, entity.TimeOut)
 			return
 		case <-done:
-			log.Println("Finished")
+			log.Infow("crawl finished")
 			return
 		case response := <-cr:
+			metrics.ObserveCrawl(response.Status)
+			log.Infow("crawl progressed", "status", response.Status, "attempts", response.Attempts)
+			span.AddEvent("status", trace.WithAttributes(attribute.String("comet.status", response.Status)))
+			timeout = idleTimeout
+			if response.Status == entity.AwaitingVerification {
+				// the user may take a while to get the code
+				timeout = crawler.VerificationTimeout + idleTimeout
+			}
+
+			if response.Image != nil {
+				err := c.blobRepo.Put(ctx, imageKey(tenantID, response.Uuid), response.Image.Data, response.Image.ContentType)
+				if err != nil {
+					log.Errorw("could not store the profile picture", "error", err)
+				}
+			}
+			c.saveArtifacts(ctx, tenantID, response.Uuid, response.Artifacts)
 			err := c.Update(ctx, &entity.CometScraper{
-				Uuid:      response.Uuid,
-				Status:    response.Status,
-				Applicant: response.Applicant,
-				TimeTaken: response.TimeTaken,
+				Uuid:       response.Uuid,
+				Status:     response.Status,
+				Applicant:  response.Applicant,
+				TimeTaken:  response.TimeTaken,
+				Attempts:   response.Attempts,
+				Checkpoint: response.Checkpoint,
 			})
 			if err != nil {
-				log.Println(err)
+				log.Errorw("could not save the crawl", "error", err)
 				return
 			}
+			if response.Status == entity.Success {
+				c.saveVersion(ctx, tenantID, response)
+			}
 		case <-ctx.Done():
-			log.Println("Done")
-			_ = c.UpsertStatus(processUuid, entity.Fail)
-			return
+			status := entity.Fail
+			if c.Draining() {
+				status = entity.Interrupted
+			}
+			log.Warnw("crawl cancelled", "status", status)
+			// the crawl's context is done, the status is saved without its cancellation
+			_ = c.UpsertStatus(tracing.Detach(ctx), processUuid, status)
+			metrics.ObserveCrawl(status)
+			span.SetStatus(codes.Error, status)
+
+			// the crawler reports its last steps before closing the browser
+			for {
+				select {
+				case <-cr:
+				case <-done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// saveVersion keeps the candidate of a successful crawl as the next version of its person,
+// its name encrypted as in comet_scraper
+func (c *cometScraperUsecase) saveVersion(ctx context.Context, tenantID string, response crawler.Response) {
+	candidate := response.Applicant
+	candidate.Name = encryptName(response.Uuid, candidate.Name)
+
+	now := time.Now()
+	err := c.versionRepo.Create(ctx, &entity.CometScraperVersion{
+		Uuid:      response.Uuid,
+		TenantID:  tenantID,
+		PersonKey: personKey(response.Uuid, response.Checkpoint),
+		Applicant: candidate,
+		TimeTaken: response.TimeTaken,
+		CrawledAt: now,
+		CreatedAt: now,
+	})
+	if err != nil {
+		logger.WithContext(ctx, c.logger).Errorw("could not save the candidate's version", "error", err)
+	}
+}
+
+// listenCodes registers the channel the verification codes submitted for the process are sent to
+func (c *cometScraperUsecase) listenCodes(id string) <-chan string {
+	c.codesMu.Lock()
+	defer c.codesMu.Unlock()
+
+	codes := make(chan string, 1)
+	c.codes[id] = codes
+	return codes
+}
+
+func (c *cometScraperUsecase) forgetCodes(id string) {
+	c.codesMu.Lock()
+	defer c.codesMu.Unlock()
+
+	delete(c.codes, id)
+}
+
+// SubmitVerification hands the code the user received to the crawl paused on the login challenge
+func (c *cometScraperUsecase) SubmitVerification(ctx context.Context, id string, request *request.VerificationCometScraperReq) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.SubmitVerification", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	comet, err := c.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comet.Status != entity.AwaitingVerification {
+		return utils.NewBadRequestError("process is not awaiting a verification code")
+	}
+
+	c.codesMu.Lock()
+	codes, ok := c.codes[id]
+	c.codesMu.Unlock()
+	if !ok {
+		return utils.NewNotFoundError("process is not running on this instance")
+	}
+
+	select {
+	case codes <- request.Code:
+		return nil
+	default:
+		return utils.NewBadRequestError("a verification code is already being checked")
+	}
+}
+
+// saveArtifacts stores the captures of a failed crawl, one failing to be saved doesn't prevent the others
+func (c *cometScraperUsecase) saveArtifacts(ctx context.Context, tenantID, id string, artifacts []crawler.Artifact) {
+	for _, artifact := range artifacts {
+		err := c.blobRepo.Put(ctx, artifactKey(tenantID, id, artifact.Name), artifact.Data, artifact.ContentType)
+		if err != nil {
+			logger.WithContext(ctx, c.logger).Errorw("could not store the artifact", "artifact", artifact.Name, "error", err)
+			continue
+		}
+
+		err = c.artifactRepo.Create(ctx, &entity.Artifact{
+			Uuid:        id,
+			TenantID:    tenantID,
+			Name:        artifact.Name,
+			Step:        artifact.Step,
+			ContentType: artifact.ContentType,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			logger.WithContext(ctx, c.logger).Errorw("could not save the artifact", "artifact", artifact.Name, "error", err)
 		}
 	}
 }
 
 func (c *cometScraperUsecase) Update(ctx context.Context, cometScraper *entity.CometScraper) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Update", trace.WithAttributes(tracing.ProcessUuid.String(cometScraper.Uuid)))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	comet, err := c.cometScraperRepo.GetByID(ctx, cometScraper.Uuid)
+	tenantID := utils.GetTenantID(ctx)
+	comet, err := c.cometScraperRepo.GetByID(ctx, tenantID, cometScraper.Uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = utils.NewNotFoundError("scraper not found")
@@ -105,25 +779,29 @@ func (c *cometScraperUsecase) Update(ctx context.Context, cometScraper *entity.C
 		return
 	}
 
-	if comet.Applicant.Name != "" {
-		cometScraper.Applicant.Name = utils.Encrypt(cometScraper.Uuid, cometScraper.Applicant.Name)
-	}
+	cometScraper.Applicant.Name = encryptName(cometScraper.Uuid, cometScraper.Applicant.Name)
 
 	comet.Status = cometScraper.Status
 	comet.TimeTaken = cometScraper.TimeTaken
+	comet.Attempts = cometScraper.Attempts
+	comet.Checkpoint = cometScraper.Checkpoint
 	comet.Applicant = cometScraper.Applicant
-	cometScraper.UpdatedAt = time.Now()
+	comet.UpdatedAt = time.Now()
 
 	err = c.cometScraperRepo.Update(ctx, &comet)
-	_ = c.redisRepo.Delete("cometScrapers")
+	c.invalidateCache(ctx, tenantID)
 	return
 }
 
-func (c *cometScraperUsecase) UpsertStatus(id string, status string) (err error) {
-	ctx, cancel := context.WithCancel(context.Background())
+func (c *cometScraperUsecase) UpsertStatus(ctx context.Context, id string, status string) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.UpsertStatus", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	comet, err := c.cometScraperRepo.GetByID(ctx, id)
+	tenantID := utils.GetTenantID(ctx)
+	comet, err := c.cometScraperRepo.GetByID(ctx, tenantID, id)
 	comet.Status = status
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -137,17 +815,22 @@ func (c *cometScraperUsecase) UpsertStatus(id string, status string) (err error)
 	comet.UpdatedAt = time.Now()
 
 	err = c.cometScraperRepo.UpdateStatus(ctx, &comet)
-	_ = c.redisRepo.Delete("cometScrapers")
+	c.invalidateCache(ctx, tenantID)
 
 	return
 }
 
 func (c *cometScraperUsecase) Create(ctx context.Context, cometScraper entity.CometScraper) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Create", trace.WithAttributes(tracing.ProcessUuid.String(cometScraper.Uuid)))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	tenantID := utils.GetTenantID(ctx)
 	err = c.cometScraperRepo.Create(ctx, &entity.CometScraper{
 		Uuid:      cometScraper.Uuid,
+		TenantID:  tenantID,
 		Status:    cometScraper.Status,
 		Applicant: cometScraper.Applicant,
 		TimeTaken: cometScraper.TimeTaken,
@@ -155,52 +838,221 @@ func (c *cometScraperUsecase) Create(ctx context.Context, cometScraper entity.Co
 		UpdatedAt: time.Now(),
 	})
 
-	_ = c.redisRepo.Delete("cometScrapers")
+	c.invalidateCache(ctx, tenantID)
 
 	return
 }
 
 func (c *cometScraperUsecase) GetByID(ctx context.Context, id string) (cometScraper entity.CometScraper, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.GetByID", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	cometScraper, err = c.cometScraperRepo.GetByID(ctx, id)
+	cometScraper, err = c.cometScraperRepo.GetByID(ctx, utils.GetTenantID(ctx), id)
 	if err != nil && err == sql.ErrNoRows {
 		err = utils.NewNotFoundError("process not found")
 		return
 	}
 
-	if cometScraper.Applicant.Name != "" {
-		cometScraper.Applicant.Name = utils.Decrypt(cometScraper.Uuid, cometScraper.Applicant.Name)
+	cometScraper.Applicant.Name = decryptName(cometScraper.Uuid, cometScraper.Applicant.Name)
+	return
+}
+
+func (c *cometScraperUsecase) Fetch(ctx context.Context, request *request.FetchCometScraperReq) (page entity.CometScraperPage, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	filter, err := newFilter(request)
+	if err != nil {
+		return
+	}
+
+	tenantID := utils.GetTenantID(ctx)
+	key := c.queryCacheKey(ctx, tenantID, request)
+	pageCached, _ := c.redisRepo.Get(ctx, key)
+	if err = json.Unmarshal([]byte(pageCached), &page); err == nil {
+		return
+	}
+
+	// fetch one extra row to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	cometScrapers, err := c.cometScraperRepo.Fetch(ctx, tenantID, filter)
+	if err != nil {
+		return
+	}
+
+	if len(cometScrapers) > limit {
+		cometScrapers = cometScrapers[:limit]
+		last := cometScrapers[limit-1]
+		cursor := entity.Cursor{Value: last.CreatedAt, Uuid: last.Uuid}
+		if filter.SortBy == entity.SortByUpdatedAt {
+			cursor.Value = last.UpdatedAt
+		}
+		page.NextCursor = utils.EncodeCursor(cursor)
+	}
+
+	page.Data = cometScrapers
+	if page.Data == nil {
+		page.Data = []entity.CometScraper{}
 	}
+
+	page.Total, err = c.cometScraperRepo.Count(ctx, tenantID, filter)
+	if err != nil {
+		return
+	}
+
+	pageString, _ := json.Marshal(&page)
+	_ = c.redisRepo.Set(ctx, key, pageString, 30*time.Second)
+
 	return
 }
 
-func (c *cometScraperUsecase) Fetch(ctx context.Context) (cometScrapers []entity.CometScraper, err error) {
+func (c *cometScraperUsecase) Search(ctx context.Context, request *request.SearchCometScraperReq) (results []entity.CometScraperSearchResult, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Search")
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	cometScrapersCached, _ := c.redisRepo.Get("cometScrapers")
-	if err = json.Unmarshal([]byte(cometScrapersCached), &cometScrapers); err == nil {
+	limit := request.Limit
+	if limit == 0 {
+		limit = entity.DefaultFetchLimit
+	}
+
+	results, err = c.cometScraperRepo.Search(ctx, utils.GetTenantID(ctx), request.Q, limit)
+	if err != nil {
+		return
+	}
+
+	for i := range results {
+		results[i].Applicant.Name = decryptName(results[i].Uuid, results[i].Applicant.Name)
+	}
+
+	if results == nil {
+		results = []entity.CometScraperSearchResult{}
+	}
+
+	return
+}
+
+func (c *cometScraperUsecase) Export(ctx context.Context, id string, request *request.ExportCometScraperReq) (document export.Document, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Export", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	cometScraper, err := c.GetByID(ctx, id)
+	if err != nil {
 		return
 	}
 
-	cometScrapers, err = c.cometScraperRepo.Fetch(ctx)
+	document, err = c.exportRenderer.Render(request.Format, cometScraper)
+	if err == export.ErrUnknownFormat {
+		err = utils.NewBadRequestError(err.Error())
+	}
+
+	return
+}
+
+func (c *cometScraperUsecase) Stream(ctx context.Context, request *request.StreamCometScraperReq, fn func(entity.CometScraper) error) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Stream")
+	defer func() { tracing.End(span, err) }()
+
+	var since *time.Time
+	if request.Since != "" {
+		parsed, err := time.Parse(time.RFC3339, request.Since)
+		if err != nil {
+			return utils.NewBadRequestError("invalid since")
+		}
+		since = &parsed
+	}
+
+	return c.cometScraperRepo.Stream(ctx, utils.GetTenantID(ctx), since, func(cometScraper entity.CometScraper) error {
+		cometScraper.Applicant.Name = decryptName(cometScraper.Uuid, cometScraper.Applicant.Name)
+		return fn(cometScraper)
+	})
+}
+
+func (c *cometScraperUsecase) GetImage(ctx context.Context, id string) (data []byte, contentType string, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.GetImage", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID := utils.GetTenantID(ctx)
+	_, err = c.cometScraperRepo.GetByID(ctx, tenantID, id)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("process not found")
+		}
 		return
 	}
 
-	cometScrapersString, _ := json.Marshal(&cometScrapers)
-	_ = c.redisRepo.Set("cometScrapers", cometScrapersString, 30*time.Second)
+	data, contentType, err = c.blobRepo.Get(ctx, imageKey(tenantID, id))
+	if err == blob.ErrNotFound {
+		err = utils.NewNotFoundError("image not found")
+	}
 
 	return
 }
 
+func (c *cometScraperUsecase) FetchArtifacts(ctx context.Context, id string) (artifacts []entity.Artifact, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.FetchArtifacts", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID := utils.GetTenantID(ctx)
+	_, err = c.cometScraperRepo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("process not found")
+		}
+		return
+	}
+
+	return c.artifactRepo.FetchByScraper(ctx, tenantID, id)
+}
+
+func (c *cometScraperUsecase) GetArtifact(ctx context.Context, id, name string) (data []byte, contentType string, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.GetArtifact", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tenantID := utils.GetTenantID(ctx)
+	artifact, err := c.artifactRepo.GetByName(ctx, tenantID, id, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.NewNotFoundError("artifact not found")
+		}
+		return
+	}
+
+	data, _, err = c.blobRepo.Get(ctx, artifactKey(tenantID, id, artifact.Name))
+	if err == blob.ErrNotFound {
+		err = utils.NewNotFoundError("artifact not found")
+	}
+
+	return data, artifact.ContentType, err
+}
+
 func (c *cometScraperUsecase) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.Delete", trace.WithAttributes(tracing.ProcessUuid.String(id)))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	_, err = c.cometScraperRepo.GetByID(ctx, id)
+	tenantID := utils.GetTenantID(ctx)
+	_, err = c.cometScraperRepo.GetByID(ctx, tenantID, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = utils.NewNotFoundError("Process not found")
@@ -209,7 +1061,38 @@ func (c *cometScraperUsecase) Delete(ctx context.Context, id string) (err error)
 		return
 	}
 
-	_ = c.redisRepo.Delete("cometScrapers")
+	// the blobs are listed before their records go with the process
+	artifacts, err := c.artifactRepo.FetchByScraper(ctx, tenantID, id)
+	if err != nil {
+		return
+	}
+
+	err = c.cometScraperRepo.Delete(ctx, tenantID, id)
+	if err != nil {
+		return
+	}
+
+	c.invalidateCache(ctx, tenantID)
+	c.deleteBlobs(ctx, tenantID, id, artifacts)
 
 	return
 }
+
+// deleteBlobs removes the profile picture and the artifacts of a deleted process, those that fail are only logged
+func (c *cometScraperUsecase) deleteBlobs(ctx context.Context, tenantID, id string, artifacts []entity.Artifact) {
+	keys := []string{imageKey(tenantID, id)}
+	for _, artifact := range artifacts {
+		keys = append(keys, artifactKey(tenantID, id, artifact.Name))
+	}
+
+	for _, key := range keys {
+		if err := c.blobRepo.Delete(ctx, key); err != nil {
+			logger.WithContext(ctx, c.logger).Errorw("could not delete the blob", "key", key, "error", err)
+		}
+	}
+}
+
+// Running is how many crawls this instance is running
+func (c *cometScraperUsecase) Running() int {
+	return int(atomic.LoadInt64(&c.running))
+}
@@ -0,0 +1,309 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/infrastructure/metrics"
+	"cometScraper/mocks"
+	"cometScraper/tools/scraper/pkg/applicant"
+	"cometScraper/tools/scraper/pkg/crawler"
+	"cometScraper/transport/request"
+	"cometScraper/usecase"
+	"cometScraper/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	cometScraperRepo := new(mocks.CometScraperRepository)
+	cometScraperRepo.On("GetByID", mock.Anything, "", "scraper").Return(entity.CometScraper{Uuid: "scraper"}, nil)
+	versionRepo := new(mocks.VersionRepository)
+	versionRepo.On("FetchByPerson", mock.Anything, "", "scraper").Return([]entity.CometScraperVersion{
+		{Uuid: "scraper", Version: 3, Applicant: applicant.Candidate{Role: "Lead"}},
+		{Uuid: "scraper", Version: 2, Applicant: applicant.Candidate{Role: "Senior"}},
+		{Uuid: "scraper", Version: 1, Applicant: applicant.Candidate{Role: "Junior"}},
+	}, nil)
+	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, nil, nil, nil, nil, nil, nil, versionRepo, new(mocks.Logger), 0)
+
+	tests := []struct {
+		name     string
+		req      request.DiffCandidateReq
+		from, to int
+		role     *applicant.Change
+		status   int
+	}{
+		{name: "latest", from: 2, to: 3, role: &applicant.Change{From: "Senior", To: "Lead"}},
+		{name: "to", req: request.DiffCandidateReq{To: 2}, from: 1, to: 2, role: &applicant.Change{From: "Junior", To: "Senior"}},
+		{name: "from and to", req: request.DiffCandidateReq{From: 3, To: 1}, from: 3, to: 1, role: &applicant.Change{From: "Lead", To: "Junior"}},
+		{name: "first", req: request.DiffCandidateReq{To: 1}, status: http.StatusBadRequest},
+		{name: "unknown", req: request.DiffCandidateReq{From: 4}, status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff, err := cometScraperUC.Diff(context.Background(), "scraper", &test.req)
+			if test.status != 0 {
+				httpErr, ok := err.(utils.HttpErr)
+				require.True(t, ok)
+				assert.Equal(t, test.status, httpErr.Status())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.from, diff.From)
+			assert.Equal(t, test.to, diff.To)
+			assert.Equal(t, test.role, diff.Role)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("deletes the process, its candidate and its blobs", func(t *testing.T) {
+		cometScraperRepo := mocks.NewCometScraperRepository(t)
+		cometScraperRepo.On("GetByID", mock.Anything, "", "scraper").Return(entity.CometScraper{Uuid: "scraper"}, nil)
+		cometScraperRepo.On("Delete", mock.Anything, "", "scraper").Return(nil)
+		artifactRepo := mocks.NewArtifactRepository(t)
+		artifactRepo.On("FetchByScraper", mock.Anything, "", "scraper").Return([]entity.Artifact{{Name: "login.png"}, {Name: "login.html"}}, nil)
+		blobRepo := mocks.NewBlobRepository(t)
+		blobRepo.On("Delete", mock.Anything, "/scraper").Return(nil)
+		blobRepo.On("Delete", mock.Anything, "/scraper.artifacts/login.png").Return(errors.New("unreachable"))
+		blobRepo.On("Delete", mock.Anything, "/scraper.artifacts/login.html").Return(nil)
+		redisRepo := new(mocks.RedisRepository)
+		redisRepo.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockLogger := new(mocks.Logger)
+		mockLogger.On("Errorw", "could not delete the blob", "key", "/scraper.artifacts/login.png", "error", mock.Anything).Return()
+		cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, nil, nil, blobRepo, artifactRepo, nil, nil, mockLogger, 0)
+
+		// a blob failing to be deleted doesn't fail the deletion, nor stop the others
+		err := cometScraperUC.Delete(context.Background(), "scraper")
+		assert.NoError(t, err)
+		redisRepo.AssertCalled(t, "Set", mock.Anything, "cometScrapers:", mock.Anything, mock.Anything)
+		mockLogger.AssertExpectations(t)
+	})
+
+	t.Run("unknown process", func(t *testing.T) {
+		cometScraperRepo := mocks.NewCometScraperRepository(t)
+		cometScraperRepo.On("GetByID", mock.Anything, "", "unknown").Return(entity.CometScraper{}, sql.ErrNoRows)
+		cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, nil, nil, nil, nil, nil, nil, nil, new(mocks.Logger), 0)
+
+		err := cometScraperUC.Delete(context.Background(), "unknown")
+		httpErr, ok := err.(utils.HttpErr)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, httpErr.Status())
+	})
+}
+
+func TestFetchVersionsByPerson(t *testing.T) {
+	sum := sha256.Sum256([]byte("https://app.comet.co/freelancer/jane-doe"))
+	person := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		resumeUrl string
+		key       string
+	}{
+		{"profile url", "https://app.comet.co/freelancer/jane-doe", person},
+		{"same profile url written differently", " https://app.comet.co/Freelancer/Jane-Doe/?ref=mail#skills", person},
+		{"surrounded by ascii whitespace", "\t\nhttps://app.comet.co/freelancer/jane-doe\r\n", person},
+		{"no profile url", "", "scraper"},
+		{"nothing left once normalized", " /?ref=mail", "scraper"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cometScraperRepo := new(mocks.CometScraperRepository)
+			cometScraperRepo.On("GetByID", mock.Anything, "", "scraper").Return(entity.CometScraper{
+				Uuid:       "scraper",
+				Checkpoint: entity.Checkpoint{Stage: entity.StepSkillsAndExperiences, ResumeUrl: test.resumeUrl},
+			}, nil)
+			versionRepo := mocks.NewVersionRepository(t)
+			versionRepo.On("FetchByPerson", mock.Anything, "", test.key).Return([]entity.CometScraperVersion{}, nil)
+			cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, nil, nil, nil, nil, nil, nil, versionRepo, new(mocks.Logger), 0)
+
+			_, err := cometScraperUC.FetchVersions(context.Background(), "scraper")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResumePlaintextName(t *testing.T) {
+	const id = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	tests := []struct {
+		name   string
+		status string
+	}{
+		{"timed out", entity.TimeOut},
+		{"interrupted", entity.Interrupted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the first update of older crawls saved the name in plaintext
+			stored := entity.CometScraper{
+				Uuid:       id,
+				Status:     test.status,
+				Applicant:  applicant.Candidate{Name: "Jane Doe", Role: "Developer"},
+				Checkpoint: entity.Checkpoint{Stage: entity.StepBaseInfo, Skills: 2, Experiences: 1},
+				Attempts:   1,
+			}
+			cometScraperRepo := new(mocks.CometScraperRepository)
+			cometScraperRepo.On("GetByID", mock.Anything, "", id).Return(stored, nil)
+			cometScraperRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+			redisRepo := new(mocks.RedisRepository)
+			redisRepo.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockLogger := new(mocks.Logger)
+			mockLogger.On("With", "uuid", id).Return(mockLogger)
+			mockLogger.On("Infow", mock.Anything).Return()
+
+			checkpoints := make(chan crawler.Checkpoint, 1)
+			cometCrawler := new(mocks.CometScraper)
+			cometCrawler.On("StartCrawling", mock.Anything, id, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				checkpoints <- args.Get(3).(crawler.Checkpoint)
+				close(args.Get(5).(chan struct{}))
+			}).Return()
+			cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, cometCrawler, nil, nil, nil, nil, nil, mockLogger, 0)
+
+			err := cometScraperUC.Resume(context.Background(), id, &request.CreateCometScraperReq{Email: "jane@doe.com", Password: "secret"})
+			require.NoError(t, err)
+
+			select {
+			case checkpoint := <-checkpoints:
+				assert.Equal(t, stored.Checkpoint, checkpoint.Checkpoint)
+				assert.Equal(t, "Jane Doe", checkpoint.Applicant.Name)
+				assert.Equal(t, 1, checkpoint.Attempts)
+			case <-time.After(time.Second):
+				t.Fatal("the crawl wasn't resumed")
+			}
+			require.NoError(t, cometScraperUC.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestRecrawlWithSessionCookies(t *testing.T) {
+	const id = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	cometScraperRepo := new(mocks.CometScraperRepository)
+	cometScraperRepo.On("GetByID", mock.Anything, "", id).Return(entity.CometScraper{Uuid: id, Status: entity.Success}, nil)
+	cometScraperRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+	redisRepo := new(mocks.RedisRepository)
+	redisRepo.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("With", "uuid", id).Return(mockLogger)
+	mockLogger.On("Infow", mock.Anything).Return()
+
+	started := make(chan crawler.Credentials, 1)
+	cometCrawler := new(mocks.CometScraper)
+	cometCrawler.On("StartCrawling", mock.Anything, id, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		started <- args.Get(2).(crawler.Credentials)
+		close(args.Get(5).(chan struct{}))
+	}).Return()
+	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, cometCrawler, nil, nil, nil, nil, nil, mockLogger, 0)
+
+	cookie := request.SessionCookie{Name: "session", Value: "token", Domain: ".comet.co", Path: "/", Secure: true, HttpOnly: true}
+	err := cometScraperUC.Recrawl(context.Background(), id, &request.CreateCometScraperReq{Cookies: []request.SessionCookie{cookie}})
+	require.NoError(t, err)
+
+	select {
+	case credentials := <-started:
+		assert.Equal(t, []crawler.Cookie{{Name: "session", Value: "token", Domain: ".comet.co", Path: "/", Secure: true, HTTPOnly: true}}, credentials.Cookies)
+	case <-time.After(time.Second):
+		t.Fatal("the crawl wasn't started")
+	}
+	require.NoError(t, cometScraperUC.Shutdown(context.Background()))
+}
+
+func TestStartBatchQueueDepth(t *testing.T) {
+	cometScraperRepo := new(mocks.CometScraperRepository)
+	cometScraperRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	cometScraperRepo.On("GetByID", mock.Anything, "", mock.Anything).Return(entity.CometScraper{Status: entity.Queued}, nil)
+	cometScraperRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+	redisRepo := new(mocks.RedisRepository)
+	redisRepo.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	batchRepo := new(mocks.BatchRepository)
+	batchRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("With", mock.Anything, mock.Anything).Return(mockLogger)
+	mockLogger.On("Infow", mock.Anything).Return()
+
+	release := make(chan struct{})
+	cometCrawler := new(mocks.CometScraper)
+	cometCrawler.On("GetUuid").Return("batch").Once()
+	cometCrawler.On("GetUuid").Return("first").Once()
+	cometCrawler.On("GetUuid").Return("second").Once()
+	cometCrawler.On("StartCrawling", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		<-release
+		close(args.Get(5).(chan struct{}))
+	}).Return()
+	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, cometCrawler, nil, nil, nil, batchRepo, nil, mockLogger, 1)
+
+	items := []request.CreateCometScraperReq{{Email: "jane@doe.com", Password: "secret"}, {Email: "john@doe.com", Password: "secret"}}
+	_, err := cometScraperUC.StartBatch(context.Background(), &request.CreateBatchCometScraperReq{Items: items})
+	require.NoError(t, err)
+
+	// the first crawl holds the only slot, the second one waits for it
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.CrawlQueueDepth) == 1 && testutil.ToFloat64(metrics.CrawlsInFlight) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// whether the second crawl starts or is interrupted by the shutdown, it leaves the queue
+	close(release)
+	require.NoError(t, cometScraperUC.Shutdown(context.Background()))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.CrawlQueueDepth))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.CrawlsInFlight))
+}
+
+func TestGetByIDName(t *testing.T) {
+	const id = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	encrypted := utils.Encrypt(id, "Jane Doe")
+	tests := []struct {
+		name   string
+		stored string
+		want   string
+	}{
+		{"encrypted", "enc:" + encrypted, "Jane Doe"},
+		{"plaintext", "Jane Doe", "Jane Doe"},
+		// only the marker tells a name was encrypted, an unmarked one is never decrypted
+		{"plaintext looking like a ciphertext", encrypted, encrypted},
+		{"undecryptable", "enc:not base64", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cometScraperRepo := new(mocks.CometScraperRepository)
+			cometScraperRepo.On("GetByID", mock.Anything, "", id).Return(entity.CometScraper{Uuid: id, Applicant: applicant.Candidate{Name: test.stored}}, nil)
+			cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, nil, nil, nil, nil, nil, nil, nil, new(mocks.Logger), 0)
+
+			cometScraper, err := cometScraperUC.GetByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, cometScraper.Applicant.Name)
+		})
+	}
+}
+
+func TestUpdateMarksEncryptedName(t *testing.T) {
+	const id = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	cometScraperRepo := new(mocks.CometScraperRepository)
+	cometScraperRepo.On("GetByID", mock.Anything, "", id).Return(entity.CometScraper{Uuid: id}, nil)
+	var stored entity.CometScraper
+	cometScraperRepo.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = *args.Get(1).(*entity.CometScraper)
+	}).Return(nil)
+	redisRepo := new(mocks.RedisRepository)
+	redisRepo.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	cometScraperUC := usecase.NewCometScraperUsecase(cometScraperRepo, redisRepo, nil, nil, nil, nil, nil, nil, new(mocks.Logger), 0)
+
+	err := cometScraperUC.Update(context.Background(), &entity.CometScraper{Uuid: id, Status: entity.Success, Applicant: applicant.Candidate{Name: "Jane Doe"}})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(stored.Applicant.Name, "enc:"))
+	assert.Equal(t, "Jane Doe", utils.Decrypt(id, strings.TrimPrefix(stored.Applicant.Name, "enc:")))
+}
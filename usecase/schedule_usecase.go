@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/infrastructure/tracing"
+	"cometScraper/repository/pgsql"
+	"cometScraper/transport/request"
+	"cometScraper/utils"
+	"cometScraper/utils/logger"
+	"github.com/robfig/cron/v3"
+	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScheduleUsecase represent the re-crawl schedule's usecase contract
+type ScheduleUsecase interface {
+	Create(ctx context.Context, request *request.CreateScheduleReq) (entity.Schedule, error)
+	Fetch(ctx context.Context) ([]entity.Schedule, error)
+	GetByID(ctx context.Context, id string) (entity.Schedule, error)
+	Delete(ctx context.Context, id string) error
+	RunDue(ctx context.Context) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type scheduleUsecase struct {
+	scheduleRepo   pgsql.ScheduleRepository
+	cometScraperUC CometScraperUsecase
+	logger         logger.Logger
+	// secret encrypts the stored credentials, schedules can't be created without it
+	secret string
+}
+
+// NewScheduleUsecase will create new a scheduleUsecase object representation of ScheduleUsecase interface
+func NewScheduleUsecase(scheduleRepo pgsql.ScheduleRepository, cometScraperUC CometScraperUsecase, logger logger.Logger, secret string) ScheduleUsecase {
+	return &scheduleUsecase{
+		scheduleRepo:   scheduleRepo,
+		cometScraperUC: cometScraperUC,
+		logger:         logger,
+		secret:         secret,
+	}
+}
+
+// dueBatchSize is how many due schedules a poll runs at most, the others wait for the next one
+const dueBatchSize = 20
+
+// credentialsKey derives a key of the length utils.Encrypt needs from the configured secret
+func (s *scheduleUsecase) credentialsKey() string {
+	sum := sha256.Sum256([]byte(s.secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *scheduleUsecase) Create(ctx context.Context, request *request.CreateScheduleReq) (schedule entity.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.schedule.Create", trace.WithAttributes(tracing.ProcessUuid.String(request.ScraperUuid)))
+	defer func() { tracing.End(span, err) }()
+
+	if s.secret == "" {
+		err = utils.NewServiceUnavailableError("schedules are disabled, SCHEDULE_SECRET is not set")
+		return
+	}
+
+	if !request.Consent {
+		err = utils.NewBadRequestError("the account owner must consent to the credentials or cookies being stored")
+		return
+	}
+
+	tenantID := utils.GetTenantID(ctx)
+	if _, err = s.cometScraperUC.GetByID(ctx, request.ScraperUuid); err != nil {
+		return
+	}
+
+	total, err := s.scheduleRepo.Count(ctx, tenantID)
+	if err != nil {
+		err = utils.NewInternalServerError(err)
+		return
+	}
+	if total >= entity.MaxSchedules {
+		err = utils.NewBadRequestError("too many schedules, delete some first")
+		return
+	}
+
+	cronSchedule, err := cron.ParseStandard(request.Cron)
+	if err != nil {
+		err = utils.NewBadRequestError(err.Error())
+		return
+	}
+
+	credentials, err := json.Marshal(request.CreateCometScraperReq)
+	if err != nil {
+		err = utils.NewInternalServerError(err)
+		return
+	}
+
+	now := time.Now()
+	schedule = entity.Schedule{
+		Uuid:        uuid.NewV4().String(),
+		TenantID:    tenantID,
+		ScraperUuid: request.ScraperUuid,
+		Cron:        request.Cron,
+		Credentials: utils.Encrypt(s.credentialsKey(), string(credentials)),
+		NextRunAt:   cronSchedule.Next(now),
+		CreatedAt:   now,
+	}
+	if err = s.scheduleRepo.Create(ctx, &schedule); err != nil {
+		err = utils.NewInternalServerError(err)
+	}
+	return
+}
+
+func (s *scheduleUsecase) Fetch(ctx context.Context) (schedules []entity.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.schedule.Fetch")
+	defer func() { tracing.End(span, err) }()
+
+	return s.scheduleRepo.Fetch(ctx, utils.GetTenantID(ctx), entity.MaxSchedules)
+}
+
+func (s *scheduleUsecase) GetByID(ctx context.Context, id string) (schedule entity.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.schedule.GetByID", trace.WithAttributes(attribute.String("comet.schedule", id)))
+	defer func() { tracing.End(span, err) }()
+
+	schedule, err = s.scheduleRepo.GetByID(ctx, utils.GetTenantID(ctx), id)
+	if err == sql.ErrNoRows {
+		err = utils.NewNotFoundError("schedule not found")
+	}
+	return
+}
+
+func (s *scheduleUsecase) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "usecase.schedule.Delete", trace.WithAttributes(attribute.String("comet.schedule", id)))
+	defer func() { tracing.End(span, err) }()
+
+	err = s.scheduleRepo.Delete(ctx, utils.GetTenantID(ctx), id)
+	if err == sql.ErrNoRows {
+		err = utils.NewNotFoundError("schedule not found")
+	}
+	return
+}
+
+// RunDue re-crawls the processes whose schedule is due, returning how many it started.
+// A run is claimed before being started, so only one instance starts it.
+func (s *scheduleUsecase) RunDue(ctx context.Context) (started int, err error) {
+	ctx, span := tracing.Start(ctx, "usecase.schedule.RunDue")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	due, err := s.scheduleRepo.FetchDue(ctx, now, dueBatchSize)
+	if err != nil {
+		return
+	}
+
+	for i := range due {
+		if s.cometScraperUC.Draining() {
+			return
+		}
+
+		schedule := &due[i]
+		cronSchedule, errParse := cron.ParseStandard(schedule.Cron)
+		if errParse != nil {
+			// validated on creation, only a hand edited row gets here
+			logger.WithContext(ctx, s.logger).Errorw("invalid schedule", "schedule", schedule.Uuid, "error", errParse)
+			continue
+		}
+
+		var claimed bool
+		claimed, err = s.scheduleRepo.Claim(ctx, schedule, cronSchedule.Next(now))
+		if err != nil {
+			return
+		}
+		if !claimed {
+			continue
+		}
+
+		s.run(ctx, schedule, now)
+		started++
+	}
+
+	return
+}
+
+// run re-crawls a schedule's process as its tenant, recording why it couldn't be started
+func (s *scheduleUsecase) run(ctx context.Context, schedule *entity.Schedule, now time.Time) {
+	ctx = context.WithValue(ctx, entity.TenantIDKey, schedule.TenantID)
+	log := logger.WithContext(context.WithValue(ctx, entity.ProcessUuidKey, schedule.ScraperUuid), s.logger)
+
+	var credentials request.CreateCometScraperReq
+	err := json.Unmarshal([]byte(utils.Decrypt(s.credentialsKey(), schedule.Credentials)), &credentials)
+	if err == nil {
+		err = s.cometScraperUC.Recrawl(ctx, schedule.ScraperUuid, &credentials)
+	}
+
+	runError := ""
+	if err != nil {
+		runError = err.Error()
+		log.Warnw("could not run the schedule", "schedule", schedule.Uuid, "error", err)
+	} else {
+		log.Infow("schedule started a re-crawl", "schedule", schedule.Uuid)
+	}
+
+	if err = s.scheduleRepo.SaveRun(ctx, schedule.Uuid, now, runError); err != nil {
+		log.Errorw("could not save the schedule run", "schedule", schedule.Uuid, "error", err)
+	}
+}
+
+// Run polls the due schedules every interval until ctx is done
+func (s *scheduleUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunDue(ctx); err != nil {
+				s.logger.Errorw("could not run the due schedules", "error", err)
+			}
+		}
+	}
+}
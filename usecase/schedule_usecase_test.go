@@ -0,0 +1,79 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cometScraper/entity"
+	"cometScraper/mocks"
+	"cometScraper/transport/request"
+	"cometScraper/usecase"
+	"cometScraper/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRunDue(t *testing.T) {
+	scheduleRepo := mocks.NewScheduleRepository(t)
+	cometScraperUC := mocks.NewCometScraperUsecase(t)
+	mockLogger := new(mocks.Logger)
+	mockLogger.On("With", "uuid", "scraper").Return(mockLogger)
+	mockLogger.On("Infow", mock.Anything, mock.Anything, mock.Anything)
+	scheduleUC := usecase.NewScheduleUsecase(scheduleRepo, cometScraperUC, mockLogger, "secret")
+
+	credentials := request.CreateCometScraperReq{Email: "user@comet.co", Password: "pass"}
+	req := &request.CreateScheduleReq{CreateCometScraperReq: credentials, ScraperUuid: "scraper", Cron: "0 6 * * 1", Consent: true}
+	cometScraperUC.On("GetByID", mock.Anything, "scraper").Return(entity.CometScraper{Uuid: "scraper"}, nil)
+	scheduleRepo.On("Count", mock.Anything, "tenant").Return(0, nil)
+	var stored entity.Schedule
+	scheduleRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = *args.Get(1).(*entity.Schedule)
+	}).Return(nil)
+
+	ctx := context.WithValue(context.Background(), entity.TenantIDKey, "tenant")
+	schedule, err := scheduleUC.Create(ctx, req)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Credentials, "pass")
+	assert.Equal(t, time.Monday, schedule.NextRunAt.Weekday())
+
+	// only the run this instance claimed is started, with the stored credentials, as the schedule's tenant
+	due := stored
+	due.NextRunAt = time.Now().Add(-time.Minute)
+	taken := due
+	taken.Uuid = "taken"
+	nextRun := mock.MatchedBy(func(next time.Time) bool {
+		return next.After(time.Now()) && next.Weekday() == time.Monday
+	})
+	scheduleRepo.On("FetchDue", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Schedule{due, taken}, nil)
+	scheduleRepo.On("Claim", mock.Anything, mock.MatchedBy(func(s *entity.Schedule) bool { return s.Uuid == stored.Uuid }), nextRun).Return(true, nil)
+	scheduleRepo.On("Claim", mock.Anything, mock.MatchedBy(func(s *entity.Schedule) bool { return s.Uuid == "taken" }), mock.Anything).Return(false, nil)
+	cometScraperUC.On("Draining").Return(false)
+	cometScraperUC.On("Recrawl", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(entity.TenantIDKey) == "tenant"
+	}), "scraper", &credentials).Return(nil)
+	scheduleRepo.On("SaveRun", mock.Anything, stored.Uuid, mock.Anything, "").Return(nil)
+
+	started, err := scheduleUC.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, started)
+}
+
+func TestScheduleCreateDisabled(t *testing.T) {
+	scheduleUC := usecase.NewScheduleUsecase(mocks.NewScheduleRepository(t), mocks.NewCometScraperUsecase(t), new(mocks.Logger), "")
+
+	_, err := scheduleUC.Create(context.Background(), &request.CreateScheduleReq{ScraperUuid: "scraper", Cron: "0 6 * * 1", Consent: true})
+	assert.Error(t, err)
+}
+
+func TestScheduleCreateWithoutConsent(t *testing.T) {
+	scheduleUC := usecase.NewScheduleUsecase(mocks.NewScheduleRepository(t), mocks.NewCometScraperUsecase(t), new(mocks.Logger), "secret")
+	credentials := request.CreateCometScraperReq{Email: "user@comet.co", Password: "pass"}
+
+	_, err := scheduleUC.Create(context.Background(), &request.CreateScheduleReq{CreateCometScraperReq: credentials, ScraperUuid: "scraper", Cron: "0 6 * * 1"})
+	httpErr, ok := err.(utils.HttpErr)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Status())
+}
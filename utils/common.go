@@ -31,3 +31,14 @@ func GetReqID(ctx context.Context) string {
 	}
 	return ""
 }
+
+// GetTenantID get tenant id from echo context
+func GetTenantID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenantID, ok := ctx.Value(entity.TenantIDKey).(string); ok {
+		return tenantID
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"cometScraper/entity"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turn a cursor into an opaque token for clients
+func EncodeCursor(cursor entity.Cursor) string {
+	raw := cursor.Value.UTC().Format(time.RFC3339Nano) + "|" + cursor.Uuid
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parse a token built by EncodeCursor
+func DecodeCursor(token string) (cursor entity.Cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return cursor, ErrInvalidCursor
+	}
+
+	cursor.Value, err = time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	cursor.Uuid = parts[1]
+
+	return cursor, nil
+}
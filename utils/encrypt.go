@@ -37,10 +37,18 @@ func Encrypt(keyString string, text string) string {
 	return base64.URLEncoding.EncodeToString(ciphertext)
 }
 
-// Decrypt from base64 to decrypted string
+// Decrypt from base64 to decrypted string, empty when cryptoText isn't a valid ciphertext
 func Decrypt(keyString string, cryptoText string) string {
+	if len(keyString) < 32 {
+		log.Println("decryption key too short")
+		return ""
+	}
 	key := []byte(keyString[0:32])
-	ciphertext, _ := base64.URLEncoding.DecodeString(cryptoText)
+	ciphertext, err := base64.URLEncoding.DecodeString(cryptoText)
+	if err != nil {
+		log.Println(err)
+		return ""
+	}
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -51,7 +59,8 @@ func Decrypt(keyString string, cryptoText string) string {
 	// The IV needs to be unique, but not secure. Therefore it's common to
 	// include it at the beginning of the ciphertext.
 	if len(ciphertext) < aes.BlockSize {
-		panic("ciphertext too short")
+		log.Println("ciphertext too short")
+		return ""
 	}
 	iv := ciphertext[:aes.BlockSize]
 	ciphertext = ciphertext[aes.BlockSize:]
@@ -0,0 +1,30 @@
+package utils_test
+
+import (
+	"testing"
+
+	"cometScraper/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const encryptKey = "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+func TestEncryptDecrypt(t *testing.T) {
+	assert.Equal(t, "Jane Doe", utils.Decrypt(encryptKey, utils.Encrypt(encryptKey, "Jane Doe")))
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"plaintext", "Jane Doe"},
+		{"too short", "SmFuZQ=="},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Empty(t, utils.Decrypt(encryptKey, test.text))
+		})
+	}
+}
@@ -18,6 +18,7 @@ var (
 	ErrInternalServerError  = errors.New("internal server error")
 	ErrUnprocessableEntity  = errors.New("unprocessable entity")
 	ErrAuthenticationFailed = errors.New("authentication vailed")
+	ErrServiceUnavailable   = errors.New("service unavailable")
 )
 
 type HttpErr interface {
@@ -34,7 +35,7 @@ type HttpError struct {
 
 // Error  Error() interface method
 func (e HttpError) Error() string {
-	return fmt.Sprintf("status: %d - errors: %s - details: %v", e.ErrStatus, e.ErrError, e.ErrDetails)
+	return fmt.Sprintf("status: %d - errors: %s - details: %s", e.ErrStatus, e.ErrError, Redact(e.ErrDetails))
 }
 
 // Error status
@@ -103,7 +104,7 @@ func NewForbiddenError(details interface{}) HttpErr {
 
 // New Internal Server Error
 func NewInternalServerError(details interface{}) HttpErr {
-	log.Error(details.(error).Error())
+	log.Error(Redact(details))
 
 	return HttpError{
 		ErrStatus:  http.StatusInternalServerError,
@@ -121,6 +122,15 @@ func NewUnprocessableEntityError(details interface{}) HttpErr {
 	}
 }
 
+// New Service Unavailable Error
+func NewServiceUnavailableError(details interface{}) HttpErr {
+	return HttpError{
+		ErrStatus:  http.StatusServiceUnavailable,
+		ErrError:   ErrServiceUnavailable.Error(),
+		ErrDetails: details,
+	}
+}
+
 // New Invalid Input Error - Validation
 func NewInvalidInputError(errs validation.Errors) HttpErr {
 	type invalidField struct {
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"context"
+
+	"cometScraper/entity"
+)
+
+// WithContext returns a child of l adding the request ID, the crawl's UUID and its stage found in ctx to every line
+func WithContext(ctx context.Context, l Logger) Logger {
+	var keysAndValues []interface{}
+	if requestID, ok := ctx.Value(entity.RequestIDKey).(string); ok && requestID != "" {
+		keysAndValues = append(keysAndValues, "request_id", requestID)
+	}
+	if uuid, ok := ctx.Value(entity.ProcessUuidKey).(string); ok && uuid != "" {
+		keysAndValues = append(keysAndValues, "uuid", uuid)
+	}
+	if stage, ok := ctx.Value(entity.StageKey).(string); ok && stage != "" {
+		keysAndValues = append(keysAndValues, "stage", stage)
+	}
+
+	if len(keysAndValues) == 0 {
+		return l
+	}
+	return l.With(keysAndValues...)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"os"
 
 	"cometScraper/config"
@@ -11,6 +12,9 @@ import (
 type Logger interface {
 	InitLogger()
 
+	// With returns a child logger adding the key-value pairs to every line
+	With(keysAndValues ...interface{}) Logger
+
 	Debug(args ...interface{})
 	Debugf(template string, args ...interface{})
 	Debugw(msg string, keysAndValues ...interface{})
@@ -42,13 +46,20 @@ type Logger interface {
 
 type apiLogger struct {
 	cfg         *config.Config
+	output      io.Writer
 	sugarLogger *zap.SugaredLogger
 }
 
 // NewApiLogger constructor
 func NewApiLogger(cfg *config.Config) *apiLogger {
+	return NewApiLoggerWithOutput(cfg, os.Stdout)
+}
+
+// NewApiLoggerWithOutput constructor writing to output, such as stderr for tools printing their result to stdout
+func NewApiLoggerWithOutput(cfg *config.Config, output io.Writer) *apiLogger {
 	return &apiLogger{
-		cfg: cfg,
+		cfg:    cfg,
+		output: output,
 	}
 }
 
@@ -86,7 +97,7 @@ func (l *apiLogger) InitLogger() {
 
 	core := zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderCfg),
-		zapcore.AddSync(os.Stdout),
+		zapcore.AddSync(l.output),
 		zap.NewAtomicLevelAt(logLevel),
 	)
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
@@ -95,6 +106,14 @@ func (l *apiLogger) InitLogger() {
 	_ = l.sugarLogger.Sync()
 }
 
+func (l *apiLogger) With(keysAndValues ...interface{}) Logger {
+	return &apiLogger{
+		cfg:         l.cfg,
+		output:      l.output,
+		sugarLogger: l.sugarLogger.With(keysAndValues...),
+	}
+}
+
 func (l *apiLogger) Debug(args ...interface{}) {
 	l.sugarLogger.Debug(args...)
 }
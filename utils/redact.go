@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the value of every sensitive field
+const Redacted = "[REDACTED]"
+
+// DefaultSensitiveFields are redacted whatever the configuration adds
+var DefaultSensitiveFields = []string{"password", "pass", "code", "token", "secret", "authorization", "access_key", "secret_key"}
+
+// Redactor masks the values of sensitive fields before they are logged.
+// A field path is a dotted list of keys, "*" matching any key. A path without a dot matches its key at any depth.
+type Redactor struct {
+	paths    [][]string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor will create a Redactor for the given field paths, on top of the DefaultSensitiveFields
+func NewRedactor(paths ...string) *Redactor {
+	r := &Redactor{}
+	seen := map[string]bool{}
+	for _, path := range append(append([]string{}, DefaultSensitiveFields...), paths...) {
+		path = strings.ToLower(strings.TrimSpace(path))
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		keys := strings.Split(path, ".")
+		r.paths = append(r.paths, keys)
+
+		key := keys[len(keys)-1]
+		if key == "*" {
+			continue
+		}
+		// "key": "value" as found in JSON, and key=value as found in query strings and form bodies
+		r.patterns = append(r.patterns,
+			regexp.MustCompile(`(?i)("`+regexp.QuoteMeta(key)+`"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`),
+			regexp.MustCompile(`(?i)(\b`+regexp.QuoteMeta(key)+`=)([^&\s,;]+)`),
+		)
+	}
+
+	return r
+}
+
+// sensitive reports whether the value at the path of keys must be redacted
+func (r *Redactor) sensitive(keys []string) bool {
+	for _, path := range r.paths {
+		if len(path) == 1 {
+			if path[0] == "*" || path[0] == keys[len(keys)-1] {
+				return true
+			}
+			continue
+		}
+
+		if len(path) != len(keys) {
+			continue
+		}
+
+		match := true
+		for i := range path {
+			if path[i] != "*" && path[i] != keys[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Redactor) redactValue(value interface{}, keys []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childKeys := append(keys[:len(keys):len(keys)], strings.ToLower(key))
+			if r.sensitive(childKeys) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = r.redactValue(child, childKeys)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.redactValue(child, keys)
+		}
+	}
+
+	return value
+}
+
+// JSON compacts a JSON body with its sensitive fields redacted, other bodies are redacted as strings
+func (r *Redactor) JSON(data []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return r.String(string(data))
+	}
+
+	redacted, err := json.Marshal(r.redactValue(value, nil))
+	if err != nil {
+		return Redacted
+	}
+
+	return string(redacted)
+}
+
+// String redacts the sensitive fields found in free text such as error messages
+func (r *Redactor) String(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			if strings.HasPrefix(groups[2], `"`) {
+				return groups[1] + `"` + Redacted + `"`
+			}
+			return groups[1] + Redacted
+		})
+	}
+
+	return s
+}
+
+var (
+	redactorMu sync.RWMutex
+	redactor   = NewRedactor()
+)
+
+// SetSensitiveFields configures the fields Redact and RedactJSON mask, on top of the DefaultSensitiveFields
+func SetSensitiveFields(paths ...string) {
+	redactorMu.Lock()
+	defer redactorMu.Unlock()
+
+	redactor = NewRedactor(paths...)
+}
+
+func currentRedactor() *Redactor {
+	redactorMu.RLock()
+	defer redactorMu.RUnlock()
+
+	return redactor
+}
+
+// Redact masks the sensitive fields of any value about to be logged
+func Redact(value interface{}) string {
+	return currentRedactor().String(fmt.Sprint(value))
+}
+
+// RedactJSON compacts a body about to be logged, masking its sensitive fields
+func RedactJSON(data []byte) string {
+	return currentRedactor().JSON(data)
+}
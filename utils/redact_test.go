@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"cometScraper/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactorJSON(t *testing.T) {
+	redactor := utils.NewRedactor("profile.phone", "accounts.*.iban")
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"password", `{"email": "jane@doe.com", "password": "hunter2"}`, `{"email":"jane@doe.com","password":"[REDACTED]"}`},
+		{"case insensitive", `{"Password": "hunter2"}`, `{"Password":"[REDACTED]"}`},
+		{"nested at any depth", `{"credentials": {"password": "hunter2"}}`, `{"credentials":{"password":"[REDACTED]"}}`},
+		{"inside arrays", `[{"password": "hunter2"}, {"token": 42}]`, `[{"password":"[REDACTED]"},{"token":"[REDACTED]"}]`},
+		{"dotted path", `{"phone": "keep", "profile": {"phone": "0600000000"}}`, `{"phone":"keep","profile":{"phone":"[REDACTED]"}}`},
+		{"wildcard", `{"accounts": {"main": {"iban": "FR76"}}}`, `{"accounts":{"main":{"iban":"[REDACTED]"}}}`},
+		{"numbers kept as is", `{"limit": 12345678901234567890}`, `{"limit":12345678901234567890}`},
+		{"form body", `email=jane%40doe.com&password=hunter2`, `email=jane%40doe.com&password=[REDACTED]`},
+		{"empty body", ``, ``},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, redactor.JSON([]byte(test.body)))
+		})
+	}
+}
+
+func TestRedactorString(t *testing.T) {
+	redactor := utils.NewRedactor()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"json fragment", `invalid body {"password":"hun\"ter2"}`, `invalid body {"password":"[REDACTED]"}`},
+		{"query string", `GET /login?email=jane&password=hunter2 failed`, `GET /login?email=jane&password=[REDACTED] failed`},
+		{"unrelated text", `status: 500 - errors: internal server error`, `status: 500 - errors: internal server error`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, redactor.String(test.text))
+		})
+	}
+}
+
+func TestHttpErrorRedactsDetails(t *testing.T) {
+	err := utils.NewBadRequestError(errors.New(`bad credentials {"password":"hunter2"}`))
+
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.NotContains(t, utils.Redact(err), "hunter2")
+}